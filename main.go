@@ -6,17 +6,19 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/drone/drone-testng/plugin"
-	"github.com/kelseyhightower/envconfig"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
 	logrus.SetFormatter(new(formatter))
+	// Keep stdout free for the JSON summary written with PLUGIN_JSON_STDOUT
+	logrus.SetOutput(os.Stderr)
 
-	var args plugin.Args
-	if err := envconfig.Process("", &args); err != nil {
+	args, err := plugin.LoadArgs()
+	if err != nil {
 		logrus.Fatalf("\nFailed to process arguments: %s", err)
 	}
 
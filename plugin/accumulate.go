@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// accumulateResults adds results to the totals stored in filename and writes
+// the new totals back, so several invocations can be gated together. A
+// missing file starts from zero. Invocations sharing a file must not run
+// concurrently.
+func accumulateResults(filename string, results Results) (Results, error) {
+	var accumulated Results
+	data, err := os.ReadFile(filename)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &accumulated); err != nil {
+			return Results{}, fmt.Errorf("failed to decode accumulator file %s: %w", filename, err)
+		}
+	case !os.IsNotExist(err):
+		return Results{}, fmt.Errorf("failed to read accumulator file %s: %w", filename, err)
+	}
+
+	accumulated.add(results)
+
+	data, err = json.MarshalIndent(accumulated, "", "  ")
+	if err != nil {
+		return Results{}, fmt.Errorf("failed to encode accumulated results: %w", err)
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return Results{}, fmt.Errorf("failed to write accumulator file %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return Results{}, fmt.Errorf("failed to move accumulator file to %s: %w", filename, err)
+	}
+	return accumulated, nil
+}
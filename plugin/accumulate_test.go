@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecAccumulateAndFinalize(t *testing.T) {
+	accumulateFile := filepath.Join(t.TempDir(), "accumulated.json")
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		FailedFails:           3,
+		AccumulateFile:        accumulateFile,
+	}
+
+	// Each module stays below the threshold on its own and thresholds are
+	// deferred until the finalizing invocation
+	for i := 0; i < 3; i++ {
+		if err := Exec(context.Background(), args); err != nil {
+			t.Fatalf("Exec() unexpected error for module %d: %v", i+1, err)
+		}
+	}
+
+	args.FinalizeThreshold = true
+	err := Exec(context.Background(), args)
+	if err == nil || !strings.Contains(err.Error(), "number of failed tests (4) exceeded the threshold (3)") {
+		t.Errorf("Exec() expected the accumulated threshold error, got %v", err)
+	}
+}
+
+func TestAccumulateResults(t *testing.T) {
+	accumulateFile := filepath.Join(t.TempDir(), "accumulated.json")
+
+	if _, err := accumulateResults(accumulateFile, Results{Total: 3, Failures: 1, DurationMS: 15}); err != nil {
+		t.Fatalf("accumulateResults() unexpected error: %v", err)
+	}
+	accumulated, err := accumulateResults(accumulateFile, Results{Total: 2, Skipped: 1, Errors: 1, DurationMS: 5})
+	if err != nil {
+		t.Fatalf("accumulateResults() unexpected error: %v", err)
+	}
+
+	expected := Results{Total: 5, Failures: 1, Skipped: 1, Errors: 1, DurationMS: 20}
+	if accumulated != expected {
+		t.Errorf("accumulateResults() expected %+v, got %+v", expected, accumulated)
+	}
+}
@@ -0,0 +1,237 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// aggregate accumulates results across processed files. Workers fold their
+// per-file results into it as soon as a file is processed, so memory stays
+// flat regardless of how many files are matched.
+type aggregate struct {
+	mu sync.Mutex
+
+	Results      Results
+	FailedTests  []string
+	SkippedTests []string
+	DroppedNames int
+	SkippedFiles []string
+	SuiteResults map[string]Results
+	Durations    durationStats
+	Buckets      map[string]Results
+
+	// SuiteFiles counts the files each suite name appears in.
+	SuiteFiles map[string]int
+
+	// ExecutedClasses is the set of distinct classes that executed a test.
+	ExecutedClasses map[string]bool
+
+	// FileTotals holds the number of tests in each processed file.
+	FileTotals map[string]int
+
+	// Packages holds the results of each Java package.
+	Packages map[string]Results
+
+	// SystemProperties holds the system properties of the first report, by
+	// path, that has any.
+	SystemProperties     []string
+	systemPropertiesPath string
+
+	// Reports is only populated when an output requires the parsed reports.
+	Reports []fileReport
+
+	maxNames     int
+	keepReports  bool
+	firstFailure sync.Once
+}
+
+// newAggregate creates an aggregate that records at most maxNames failed and
+// skipped test names. A maxNames of zero records all names.
+func newAggregate(maxNames int, keepReports bool) *aggregate {
+	return &aggregate{
+		SuiteResults:    make(map[string]Results),
+		SuiteFiles:      make(map[string]int),
+		Buckets:         make(map[string]Results),
+		ExecutedClasses: make(map[string]bool),
+		FileTotals:      make(map[string]int),
+		Packages:        make(map[string]Results),
+		maxNames:        maxNames,
+		keepReports:     keepReports,
+	}
+}
+
+// add folds the results of a single processed file into the aggregate.
+func (a *aggregate) add(fr fileReport, failed, skipped []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.Results.add(fr.Results)
+	a.FileTotals[fr.Path] = fr.Results.Total
+	a.Durations.merge(fr.Durations)
+
+	for suite, res := range fr.SuiteResults {
+		total := a.SuiteResults[suite]
+		total.add(res)
+		a.SuiteResults[suite] = total
+		a.SuiteFiles[suite]++
+	}
+
+	for pkg, res := range fr.Packages {
+		total := a.Packages[pkg]
+		total.add(res)
+		a.Packages[pkg] = total
+	}
+
+	for bucket, res := range fr.Buckets {
+		total := a.Buckets[bucket]
+		total.add(res)
+		a.Buckets[bucket] = total
+	}
+
+	for _, class := range fr.ExecutedClasses {
+		a.ExecutedClasses[class] = true
+	}
+
+	if len(fr.SystemProperties) > 0 && (a.SystemProperties == nil || fr.Path < a.systemPropertiesPath) {
+		a.SystemProperties = fr.SystemProperties
+		a.systemPropertiesPath = fr.Path
+	}
+
+	a.FailedTests = a.appendNames(a.FailedTests, failed)
+	a.SkippedTests = a.appendNames(a.SkippedTests, skipped)
+
+	if a.keepReports {
+		fr.FailedTests = failed
+		a.Reports = append(a.Reports, fr)
+	}
+}
+
+// skip records a file that could not be processed.
+func (a *aggregate) skip(path string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.SkippedFiles = append(a.SkippedFiles, path)
+}
+
+// appendNames appends names to list without exceeding the configured cap.
+func (a *aggregate) appendNames(list, names []string) []string {
+	if a.maxNames > 0 {
+		if room := a.maxNames - len(list); room < len(names) {
+			if room < 0 {
+				room = 0
+			}
+			a.DroppedNames += len(names) - room
+			names = names[:room]
+		}
+	}
+	return append(list, names...)
+}
+
+// processFiles processes files with a bounded pool of workers and folds each
+// file's results into a shared aggregate.
+func processFiles(files []string, args Args) *aggregate {
+	agg := newAggregate(args.MaxTestNames, needsReports(args))
+	if args.StreamLargeFiles && needsReports(args) {
+		logrus.Warn("StreamLargeFiles is ignored as the configured outputs or checks need the parsed reports; every file is parsed in full")
+	}
+
+	var bucketPattern *regexp.Regexp
+	if args.GroupByDescriptionPrefix != "" {
+		re, err := compileBucketPattern(args.GroupByDescriptionPrefix)
+		if err != nil {
+			logrus.Warn(err)
+		}
+		bucketPattern = re
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+	if workers > len(files) {
+		workers = len(files)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				if shouldStream(f, args) {
+					fr, failed, skipped, err := streamReport(f, args)
+					if err == nil {
+						agg.add(fr, failed, skipped)
+						continue
+					}
+					if !errors.Is(err, errJUnitStream) {
+						logrus.Warn(fmt.Errorf("failed to process file %s: %w", f, err))
+						agg.skip(f)
+						continue
+					}
+					logrus.Infof("File %s is a JUnit report, which is not streamed; parsing it in full", f)
+				}
+
+				report, err := parseReport(f, args)
+				if err != nil {
+					logrus.Warn(fmt.Errorf("failed to process file %s: %w", f, err))
+					agg.skip(f)
+					continue
+				}
+
+				if args.HighlightFirstFailure {
+					agg.highlightFirstFailure(report, args)
+				}
+
+				details := logTestNGReportDetails(report, args)
+				fr := fileReport{
+					Path:         f,
+					Results:      details.Results,
+					SuiteResults: details.SuiteResults,
+					Durations:    reportDurationStats(report),
+				}
+				if bucketPattern != nil {
+					fr.Buckets = bucketResults(report, bucketPattern, args)
+				}
+				if args.MinClasses > 0 {
+					fr.ExecutedClasses = executedClasses(report, args)
+				}
+				if args.AggregateBy == AggregateByPackage {
+					fr.Packages = packageResults(report, details.Suites)
+				}
+				if args.LogSystemProperties {
+					fr.SystemProperties = systemProperties(report.ReporterOutput)
+				}
+				if agg.keepReports {
+					fr.Report = report
+					fr.Suites = details.Suites
+				}
+				agg.add(fr, details.FailedTests, details.SkippedTests)
+			}
+		}()
+	}
+
+	for _, f := range files {
+		jobs <- f
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Files complete in arbitrary order; sort them so outputs are deterministic
+	sort.Slice(agg.Reports, func(i, j int) bool { return agg.Reports[i].Path < agg.Reports[j].Path })
+	sort.Strings(agg.SkippedFiles)
+
+	return agg
+}
+
+// needsReports reports whether any configured output requires the parsed reports.
+func needsReports(args Args) bool {
+	return args.BazelXMLFile != "" || args.JUnitFile != "" || args.JUnitSplitBySuite || args.AllureDir != "" || args.CSVFile != "" || args.TAPFile != "" || args.CanonicalReportFile != "" ||
+		((args.OutputFile != "" || args.JSONStdout || args.PostCommand != "") && args.DetailedJSON) ||
+		args.ComparePattern != "" || args.BaselinePassingFile != ""
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAggregateNameCap(t *testing.T) {
+	agg := newAggregate(3, false)
+	agg.add(fileReport{Results: Results{Total: 4, Failures: 2, Skipped: 2}}, []string{"f1", "f2"}, []string{"s1", "s2"})
+	agg.add(fileReport{Results: Results{Total: 3, Failures: 2, Skipped: 1}}, []string{"f3", "f4"}, []string{"s3"})
+
+	expected := Results{Total: 7, Failures: 4, Skipped: 3}
+	if diff := cmp.Diff(expected, agg.Results); diff != "" {
+		t.Errorf("Results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"f1", "f2", "f3"}, agg.FailedTests); diff != "" {
+		t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"s1", "s2", "s3"}, agg.SkippedTests); diff != "" {
+		t.Errorf("Skipped tests mismatch (-want +got):\n%s", diff)
+	}
+	if agg.DroppedNames != 1 {
+		t.Errorf("Expected 1 dropped name, got %d", agg.DroppedNames)
+	}
+}
+
+func TestProcessFilesAggregatesConcurrently(t *testing.T) {
+	files := []string{
+		filepath.FromSlash("../testdata/testng-report.xml"),
+		filepath.FromSlash("../testdata/testng-report-valid.xml"),
+		filepath.FromSlash("../testdata/invalid.xml"),
+	}
+
+	agg := processFiles(files, Args{})
+
+	expected := Results{Total: 9, Failures: 3, DurationMS: 45, AssertionFailures: 3}
+	if diff := cmp.Diff(expected, agg.Results); diff != "" {
+		t.Errorf("Results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{filepath.FromSlash("../testdata/invalid.xml")}, agg.SkippedFiles); diff != "" {
+		t.Errorf("Skipped files mismatch (-want +got):\n%s", diff)
+	}
+	if len(agg.Reports) != 0 {
+		t.Errorf("Expected no retained reports when no output needs them, got %d", len(agg.Reports))
+	}
+}
+
+// BenchmarkProcessFiles reports the live heap after aggregation for rising
+// file counts. The heap-bytes metric should stay flat as the count grows.
+func BenchmarkProcessFiles(b *testing.B) {
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(os.Stderr)
+
+	data, err := os.ReadFile("../testdata/testng-report-valid.xml")
+	if err != nil {
+		b.Fatalf("Failed to read testdata: %v", err)
+	}
+
+	for _, count := range []int{10, 100, 1000} {
+		dir := b.TempDir()
+		files := make([]string, count)
+		for i := range files {
+			files[i] = filepath.Join(dir, fmt.Sprintf("report-%d.xml", i))
+			if err := os.WriteFile(files[i], data, 0644); err != nil {
+				b.Fatalf("Failed to write report: %v", err)
+			}
+		}
+
+		b.Run(fmt.Sprintf("files=%d", count), func(b *testing.B) {
+			var heap uint64
+			for i := 0; i < b.N; i++ {
+				agg := processFiles(files, Args{MaxTestNames: 100})
+
+				runtime.GC()
+				var m runtime.MemStats
+				runtime.ReadMemStats(&m)
+				heap += m.HeapAlloc
+				runtime.KeepAlive(agg)
+			}
+			b.ReportMetric(float64(heap)/float64(b.N), "heap-bytes")
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseSuiteAliases parses aliases of the form "raw=Friendly,..." into a map
+// of raw suite name to friendly name.
+func parseSuiteAliases(aliases string) (map[string]string, error) {
+	parsed := make(map[string]string)
+	for _, entry := range strings.Split(aliases, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		raw, friendly, ok := strings.Cut(entry, "=")
+		raw, friendly = strings.TrimSpace(raw), strings.TrimSpace(friendly)
+		if !ok || raw == "" || friendly == "" {
+			return nil, fmt.Errorf("invalid SuiteAliases entry '%s'. Use the format raw=Friendly", entry)
+		}
+		parsed[raw] = friendly
+	}
+	return parsed, nil
+}
+
+// suiteAlias returns the friendly name of suite, or suite itself when it
+// has no alias.
+func suiteAlias(aliases map[string]string, suite string) string {
+	if alias, ok := aliases[suite]; ok {
+		return alias
+	}
+	return suite
+}
+
+// applySuiteAliases renames the suites of report that have an alias.
+func applySuiteAliases(report *TestNGReport, aliases map[string]string) {
+	for i := range report.Suites {
+		report.Suites[i].Name = suiteAlias(aliases, report.Suites[i].Name)
+	}
+}
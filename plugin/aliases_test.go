@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuiteAliasesInSummary(t *testing.T) {
+	// The output file is only set so the parsed reports are kept
+	args := Args{SuiteAliases: "Suite1=Unit Tests, Other=Integration Tests", DetailedJSON: true, OutputFile: "summary.json"}
+	agg := processFiles([]string{"../testdata/testng-report.xml", "../testdata/errors/testng-errors.xml"}, args)
+	if len(agg.SkippedFiles) > 0 {
+		t.Fatalf("Unexpected skipped files: %v", agg.SkippedFiles)
+	}
+
+	var names []string
+	for _, suite := range buildSummary(agg.Results, agg.Reports, args).Suites {
+		names = append(names, suite.Name)
+	}
+	if diff := cmp.Diff([]string{"ErrorSuite", "Unit Tests"}, names); diff != "" {
+		t.Errorf("Summary suite names mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := agg.SuiteResults["Unit Tests"]; !ok {
+		t.Errorf("Expected suite results under the alias, got %v", agg.SuiteResults)
+	}
+	if _, ok := agg.SuiteResults["Suite1"]; ok {
+		t.Error("Expected no suite results under the raw name")
+	}
+}
+
+func TestParseSuiteAliases(t *testing.T) {
+	aliases, err := parseSuiteAliases("Surefire suite=Unit Tests,,Smoke = Smoke Tests")
+	if err != nil {
+		t.Fatalf("parseSuiteAliases() unexpected error: %v", err)
+	}
+	expected := map[string]string{"Surefire suite": "Unit Tests", "Smoke": "Smoke Tests"}
+	if diff := cmp.Diff(expected, aliases); diff != "" {
+		t.Errorf("parseSuiteAliases() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, invalid := range []string{"Unit Tests", "=Unit Tests", "Suite1="} {
+		if _, err := parseSuiteAliases(invalid); err == nil {
+			t.Errorf("parseSuiteAliases(%q) expected an error", invalid)
+		}
+	}
+}
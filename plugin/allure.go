@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AllureResult is a single test result in the Allure results format. Allure
+// reads every <uuid>-result.json file of a results directory.
+type AllureResult struct {
+	UUID          string               `json:"uuid"`
+	HistoryID     string               `json:"historyId"`
+	FullName      string               `json:"fullName"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description,omitempty"`
+	Status        string               `json:"status"`
+	StatusDetails *AllureStatusDetails `json:"statusDetails,omitempty"`
+	Stage         string               `json:"stage"`
+	Start         int64                `json:"start,omitempty"`
+	Stop          int64                `json:"stop,omitempty"`
+	Labels        []AllureLabel        `json:"labels"`
+}
+
+// AllureStatusDetails holds the failure details of a test result.
+type AllureStatusDetails struct {
+	Message string `json:"message,omitempty"`
+	Trace   string `json:"trace,omitempty"`
+}
+
+// AllureLabel is a name/value label used by Allure to group results.
+type AllureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// testTimestampLayouts are the layouts tried when parsing started-at and
+// finished-at. TestNG writes them in local time with a literal Z by default.
+var testTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000 MST",
+	"2006-01-02T15:04:05 MST",
+}
+
+// parseTestTimestamp parses a test-method timestamp, reporting whether it
+// could be parsed.
+func parseTestTimestamp(value string) (time.Time, bool) {
+	for _, layout := range testTimestampLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// allureStatus maps a test to an Allure status. Failures caused by anything
+// other than an assertion are "broken", as Allure distinguishes them.
+func allureStatus(test Test, failStatuses, skipStatuses map[string]bool) string {
+	status := strings.ToUpper(test.Status)
+	switch {
+	case failStatuses[status]:
+		if classifyFailure(test) == failureError {
+			return "broken"
+		}
+		return "failed"
+	case skipStatuses[status]:
+		return "skipped"
+	default:
+		return "passed"
+	}
+}
+
+// newAllureUUID returns a random version 4 UUID.
+func newAllureUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// buildAllureResults converts the test methods of the processed reports into
+// Allure results. Configuration methods are fixtures rather than tests in
+// Allure and are left out.
+func buildAllureResults(reports []fileReport, args Args) ([]AllureResult, error) {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	var results []AllureResult
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					if test.IsConfig {
+						continue
+					}
+
+					uuid, err := newAllureUUID()
+					if err != nil {
+						return nil, fmt.Errorf("failed to generate Allure result UUID: %w", err)
+					}
+					fullName := class.Name + "." + test.Name
+					historyID := md5.Sum([]byte(fullName))
+					result := AllureResult{
+						UUID:        uuid,
+						HistoryID:   hex.EncodeToString(historyID[:]),
+						FullName:    fullName,
+						Name:        test.Name,
+						Description: test.Description,
+						Status:      allureStatus(test, failStatuses, skipStatuses),
+						Stage:       "finished",
+						Labels: []AllureLabel{
+							{Name: "framework", Value: "testng"},
+							{Name: "suite", Value: suite.Name},
+							{Name: "package", Value: classPackage(class.Name)},
+							{Name: "testClass", Value: class.Name},
+							{Name: "testMethod", Value: test.Name},
+						},
+					}
+
+					if start, ok := parseTestTimestamp(test.StartedAt); ok {
+						result.Start = start.UnixMilli()
+						if stop, ok := parseTestTimestamp(test.FinishedAt); ok {
+							result.Stop = stop.UnixMilli()
+						} else if duration, err := strconv.ParseFloat(test.DurationMS, 64); err == nil {
+							result.Stop = result.Start + int64(duration)
+						}
+					}
+
+					if result.Status == "failed" || result.Status == "broken" {
+						result.StatusDetails = &AllureStatusDetails{
+							Message: firstLine(test.Exception),
+							Trace:   strings.TrimSpace(test.Exception),
+						}
+					}
+					results = append(results, result)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// writeAllureResults writes one <uuid>-result.json file per test to dir and
+// returns the number of files written.
+func writeAllureResults(dir string, reports []fileReport, args Args) (int, error) {
+	results, err := buildAllureResults(reports, args)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create Allure results directory %s: %w", dir, err)
+	}
+
+	for _, result := range results {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode Allure result for %s: %w", result.FullName, err)
+		}
+		path := filepath.Join(dir, result.UUID+"-result.json")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return 0, fmt.Errorf("failed to write Allure result to %s: %w", path, err)
+		}
+	}
+	return len(results), nil
+}
@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWriteAllureResults(t *testing.T) {
+	var reports []fileReport
+	for _, path := range []string{"../testdata/failures/testng-failure-kinds.xml", "../testdata/testng-report.xml"} {
+		report, err := parseReport(path, Args{})
+		if err != nil {
+			t.Fatalf("parseReport() unexpected error: %v", err)
+		}
+		reports = append(reports, fileReport{Path: path, Report: report})
+	}
+
+	dir := filepath.Join(t.TempDir(), "allure-results")
+	count, err := writeAllureResults(dir, reports, Args{})
+	if err != nil {
+		t.Fatalf("writeAllureResults() unexpected error: %v", err)
+	}
+
+	// The setUp configuration method is not a test in Allure
+	files, err := filepath.Glob(filepath.Join(dir, "*-result.json"))
+	if err != nil {
+		t.Fatalf("Failed to list Allure results: %v", err)
+	}
+	if count != 5 || len(files) != 5 {
+		t.Fatalf("Expected 5 Allure results, got count=%d files=%d", count, len(files))
+	}
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	got := make(map[string]AllureResult)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read Allure result: %v", err)
+		}
+		var result AllureResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Failed to decode Allure result %s: %v", file, err)
+		}
+		if !uuidPattern.MatchString(result.UUID) || filepath.Base(file) != result.UUID+"-result.json" {
+			t.Errorf("Allure result %s has an unexpected UUID %q", file, result.UUID)
+		}
+		got[result.FullName] = result
+	}
+
+	start := time.Date(2007, 5, 28, 12, 14, 37, 0, time.UTC).UnixMilli()
+	labels := []AllureLabel{
+		{Name: "framework", Value: "testng"},
+		{Name: "suite", Value: "FailureSuite"},
+		{Name: "package", Value: "com.test"},
+		{Name: "testClass", Value: "com.test.Failures"},
+	}
+	withMethod := func(name string) []AllureLabel {
+		return append(append([]AllureLabel(nil), labels...), AllureLabel{Name: "testMethod", Value: name})
+	}
+	expected := map[string]AllureResult{
+		"com.test.Failures.test1": {
+			FullName: "com.test.Failures.test1",
+			Name:     "test1",
+			Status:   "failed",
+			StatusDetails: &AllureStatusDetails{
+				Message: "java.lang.AssertionError: expected [1] but found [2]",
+				Trace:   "java.lang.AssertionError: expected [1] but found [2]\n                ... Removed 22 stack frames",
+			},
+			Stage:  "finished",
+			Start:  start,
+			Stop:   start,
+			Labels: withMethod("test1"),
+		},
+		"com.test.Failures.test2": {
+			FullName: "com.test.Failures.test2",
+			Name:     "test2",
+			Status:   "broken",
+			StatusDetails: &AllureStatusDetails{
+				Message: "java.lang.NullPointerException",
+				Trace:   "java.lang.NullPointerException\n                ... Removed 22 stack frames",
+			},
+			Stage:  "finished",
+			Start:  start,
+			Stop:   start,
+			Labels: withMethod("test2"),
+		},
+		"com.test.Failures.test3": {
+			FullName: "com.test.Failures.test3",
+			Name:     "test3",
+			Status:   "passed",
+			Stage:    "finished",
+			Start:    start,
+			Stop:     start,
+			Labels:   withMethod("test3"),
+		},
+	}
+	for name, want := range expected {
+		if diff := cmp.Diff(want, got[name], cmpopts.IgnoreFields(AllureResult{}, "UUID", "HistoryID")); diff != "" {
+			t.Errorf("Allure result %s mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+
+	var names []string
+	for name := range got {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{"com.test.Failures.test1", "com.test.Failures.test2", "com.test.Failures.test3", "com.test.TestOne.test1", "com.test.TestOne.test2"}, names); diff != "" {
+		t.Errorf("Allure result names mismatch (-want +got):\n%s", diff)
+	}
+	if got["com.test.TestOne.test2"].Description != "someDescription1" {
+		t.Errorf("Expected the test description, got %q", got["com.test.TestOne.test2"].Description)
+	}
+}
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// publicExceptionPackages are the packages of exception classes that come from
+// the JDK or the test frameworks. They reveal nothing about the code under
+// test, so they are kept to tell assertion failures from errors.
+var publicExceptionPackages = []string{"java.", "javax.", "org.testng.", "org.junit.", "org.opentest4j."}
+
+// anonymizeName replaces a name with a stable hash, so the same name always
+// maps to the same value.
+func anonymizeName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// anonymizeReports returns a copy of reports with class, test and group
+// method names replaced by stable hashes. Counts and statuses are unchanged.
+func anonymizeReports(reports []fileReport) []fileReport {
+	anonymized := make([]fileReport, len(reports))
+	for i, fr := range reports {
+		anonymized[i] = fr
+		anonymized[i].Report.Suites = make([]Suite, len(fr.Report.Suites))
+		for j, suite := range fr.Report.Suites {
+			anonymized[i].Report.Suites[j] = anonymizeSuite(suite)
+		}
+		anonymized[i].FailedTests = anonymizeNames(fr.FailedTests)
+	}
+	return anonymized
+}
+
+// anonymizeNames returns a copy of names with every name hashed.
+func anonymizeNames(names []string) []string {
+	anonymized := make([]string, len(names))
+	for i, name := range names {
+		anonymized[i] = anonymizeName(name)
+	}
+	return anonymized
+}
+
+// anonymizeSuite returns a copy of suite with its names hashed.
+func anonymizeSuite(suite Suite) Suite {
+	// The per-<test> and direct classes are only needed while decoding; drop
+	// them so no raw names are carried along.
+	tests := make([]SuiteTest, len(suite.Tests))
+	for i, test := range suite.Tests {
+		test.Classes = nil
+		tests[i] = test
+	}
+	suite.Tests = tests
+	suite.DirectClasses = nil
+
+	groups := make([]Group, len(suite.Groups))
+	for i, group := range suite.Groups {
+		methods := make([]Method, len(group.Methods))
+		for j, method := range group.Methods {
+			methods[j] = Method{
+				Name:      anonymizeName(method.Name),
+				Signature: anonymizeName(method.Signature),
+				ClassName: anonymizeName(method.ClassName),
+			}
+		}
+		groups[i] = Group{Name: group.Name, Methods: methods}
+	}
+	suite.Groups = groups
+
+	classes := make([]Class, len(suite.Classes))
+	for i, class := range suite.Classes {
+		testMethods := make([]Test, len(class.Tests))
+		for j, test := range class.Tests {
+			testMethods[j] = anonymizeTest(test)
+		}
+		classes[i] = Class{Name: anonymizeName(class.Name), Tests: testMethods}
+	}
+	suite.Classes = classes
+
+	return suite
+}
+
+// anonymizeTest returns a copy of test with its name, description, retry
+// analyzer and exception hashed. Stack traces are full of class and method
+// names, so the exception is replaced by its hash as a whole.
+func anonymizeTest(test Test) Test {
+	class := exceptionClass(test)
+	if class != "" && !hasAnyPrefix(class, publicExceptionPackages) {
+		class = anonymizeName(class)
+	}
+	test.ExceptionClass = class
+
+	test.Name = anonymizeName(test.Name)
+	test.Description = anonymizeOptional(test.Description)
+	test.RetryAnalyzer = anonymizeOptional(test.RetryAnalyzer)
+	test.Exception = anonymizeOptional(strings.TrimSpace(test.Exception))
+	return test
+}
+
+// anonymizeOptional hashes value, leaving empty values empty.
+func anonymizeOptional(value string) string {
+	if value == "" {
+		return ""
+	}
+	return anonymizeName(value)
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAnonymizeReports(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	reports := []fileReport{newSummaryFileReport("testng-report.xml", report, Args{})}
+	results := Results{Total: 3, Failures: 1, DurationMS: 15}
+
+	original := buildSummary(results, reports, Args{DetailedJSON: true})
+	anonymized := buildSummary(results, anonymizeReports(reports), Args{DetailedJSON: true})
+
+	// Counts are preserved
+	if anonymized.Total != original.Total || anonymized.Failures != original.Failures {
+		t.Errorf("Aggregate counts changed: %+v", anonymized)
+	}
+
+	origSuite, anonSuite := original.Suites[0], anonymized.Suites[0]
+	if anonSuite.Total != origSuite.Total || anonSuite.Failures != origSuite.Failures || len(anonSuite.Tests) != len(origSuite.Tests) {
+		t.Errorf("Suite counts changed: %+v", anonSuite)
+	}
+
+	for i, test := range anonSuite.Tests {
+		orig := origSuite.Tests[i]
+		if test.Name == orig.Name || test.Class == orig.Class {
+			t.Errorf("Test %s.%s was not anonymized", orig.Class, orig.Name)
+		}
+		if test.Name != anonymizeName(orig.Name) || test.Class != anonymizeName(orig.Class) {
+			t.Errorf("Test %s.%s is not hashed deterministically", orig.Class, orig.Name)
+		}
+		if test.Status != orig.Status {
+			t.Errorf("Status of %s changed from %s to %s", orig.Name, orig.Status, test.Status)
+		}
+		// Group attribution survives anonymization
+		if diff := cmp.Diff(orig.Groups, test.Groups); diff != "" {
+			t.Errorf("Groups of %s changed (-want +got):\n%s", orig.Name, diff)
+		}
+	}
+
+	// The input reports are not modified
+	if report.Suites[0].Classes[0].Name != "com.test.TestOne" {
+		t.Errorf("anonymizeReports() modified the input reports")
+	}
+}
+
+func TestExecAnonymizeNamesOutputs(t *testing.T) {
+	// The fixture's class, description, retry analyzer, exception class and
+	// stack traces all name the com.acme code under test
+	dir := t.TempDir()
+	outputs := map[string]string{
+		"JSON":      filepath.Join(dir, "summary.json"),
+		"CSV":       filepath.Join(dir, "results.csv"),
+		"TAP":       filepath.Join(dir, "results.tap"),
+		"JUnit":     filepath.Join(dir, "junit.xml"),
+		"Bazel":     filepath.Join(dir, "test.xml"),
+		"Canonical": filepath.Join(dir, "canonical.txt"),
+		"Slack":     filepath.Join(dir, "slack.json"),
+		"Markdown":  filepath.Join(dir, "step_summary.md"),
+	}
+	allureDir := filepath.Join(dir, "allure-results")
+	t.Setenv("GITHUB_STEP_SUMMARY", outputs["Markdown"])
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/anonymize/testng-exceptions.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		FailedFails:           10,
+		AnonymizeNames:        true,
+		DetailedJSON:          true,
+		OutputFile:            outputs["JSON"],
+		CSVFile:               outputs["CSV"],
+		TAPFile:               outputs["TAP"],
+		JUnitFile:             outputs["JUnit"],
+		BazelXMLFile:          outputs["Bazel"],
+		CanonicalReportFile:   outputs["Canonical"],
+		SlackMessageFile:      outputs["Slack"],
+		AllureDir:             allureDir,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	allureFiles, err := filepath.Glob(filepath.Join(allureDir, "*-result.json"))
+	if err != nil || len(allureFiles) != 2 {
+		t.Fatalf("Expected 2 Allure results, got %v (%v)", allureFiles, err)
+	}
+	for i, filename := range allureFiles {
+		outputs[fmt.Sprintf("Allure %d", i+1)] = filename
+	}
+
+	for name, filename := range outputs {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Errorf("%s output was not written: %v", name, err)
+			continue
+		}
+		if strings.Contains(string(data), "acme") {
+			t.Errorf("%s output leaks names of the code under test:\n%s", name, data)
+		}
+	}
+
+	// Exception classes from the JDK are kept to classify failures
+	junit, _ := os.ReadFile(outputs["JUnit"])
+	if !strings.Contains(string(junit), `type="java.lang.AssertionError"`) {
+		t.Errorf("Expected the JDK exception class to be kept in the JUnit XML:\n%s", junit)
+	}
+}
@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// checkMinAssertions returns an error when the tests asserted fewer than
+// minAssertions times in total. Assertion counts are not part of the
+// standard TestNG report, so the check is skipped when no test reported one.
+func checkMinAssertions(results Results, minAssertions int) error {
+	if minAssertions <= 0 {
+		return nil
+	}
+
+	if results.AssertionsReported == 0 {
+		logrus.Infof("\nNo test reported an assertion count; skipping the MinAssertions check")
+		return nil
+	}
+
+	if results.Assertions < minAssertions {
+		return withFailReason(FailReasonMinAssertions, fmt.Errorf("\nbuild marked as failed as the tests made %d assertions, below MinAssertions (%d)", results.Assertions, minAssertions))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestProcessFileAssertions(t *testing.T) {
+	results, err := processFile("../testdata/assertions/testng-assertions.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.Assertions != 6 || results.AssertionsReported != 3 {
+		t.Errorf("Expected 6 assertions from 3 tests, got %d from %d", results.Assertions, results.AssertionsReported)
+	}
+}
+
+func TestExecMinAssertions(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		minAssertions int
+		expectError   bool
+		expectNote    bool
+	}{
+		{name: "Above", pattern: "../testdata/assertions/testng-assertions.xml", minAssertions: 6},
+		{name: "Below", pattern: "../testdata/assertions/testng-assertions.xml", minAssertions: 7, expectError: true},
+		{name: "NotReported", pattern: "../testdata/testng-report.xml", minAssertions: 100, expectNote: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logrus.SetLevel(logrus.InfoLevel)
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			args := Args{
+				ReportFilenamePattern: tc.pattern,
+				ThresholdMode:         ThresholdModeAbsolute,
+				MinAssertions:         tc.minAssertions,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+
+			noted := false
+			for _, entry := range hook.Entries {
+				if strings.Contains(entry.Message, "skipping the MinAssertions check") {
+					noted = true
+				}
+			}
+			if noted != tc.expectNote {
+				t.Errorf("Expected skip note %v, got %v", tc.expectNote, noted)
+			}
+		})
+	}
+}
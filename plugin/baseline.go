@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// readBaselinePassing reads the class.method names of the tests that passed
+// in the baseline, one per line. Blank lines and lines starting with # are
+// skipped.
+func readBaselinePassing(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline file %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read baseline file %s: %w", filename, err)
+	}
+	return names, nil
+}
+
+// baselineRegressions returns the sorted names of the baseline tests that
+// failed in reports. Baseline tests that are missing from the reports or
+// skipped are not regressions.
+func baselineRegressions(baseline []string, reports []fileReport, args Args) []string {
+	parsed := make([]TestNGReport, 0, len(reports))
+	for _, fr := range reports {
+		parsed = append(parsed, fr.Report)
+	}
+	outcomes := testOutcomes(parsed, args)
+
+	var regressed []string
+	seen := make(map[string]bool)
+	for _, name := range baseline {
+		if outcomes[name] && !seen[name] {
+			seen[name] = true
+			regressed = append(regressed, name)
+		}
+	}
+	sort.Strings(regressed)
+	return regressed
+}
+
+// checkBaselinePassing returns an error naming every test listed in the
+// baseline file of previously passing tests that now fails.
+func checkBaselinePassing(reports []fileReport, args Args) error {
+	baseline, err := readBaselinePassing(args.BaselinePassingFile)
+	if err != nil {
+		return err
+	}
+
+	regressed := baselineRegressions(baseline, reports, args)
+	if len(regressed) > 0 {
+		return withFailReason(FailReasonBaselinePassing, fmt.Errorf("\nbuild marked as failed as %d previously passing tests now fail: %s", len(regressed), formatTestNames(regressed, args.MaxNamesLogged)))
+	}
+	return nil
+}
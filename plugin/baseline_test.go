@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecBaselinePassingFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseline    string
+		expectError string
+	}{
+		{
+			name:     "NoRegressions",
+			baseline: "# passing on main\ncom.test.TestOne.test2\ncom.test.TestOne.removedTest\n",
+		},
+		{
+			name:        "Regressed",
+			baseline:    "com.test.TestOne.test1\ncom.test.TestOne.test2\n",
+			expectError: "1 previously passing tests now fail: com.test.TestOne.test1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			baselineFile := filepath.Join(t.TempDir(), "baseline.txt")
+			if err := os.WriteFile(baselineFile, []byte(tc.baseline), 0644); err != nil {
+				t.Fatalf("Failed to write baseline file: %v", err)
+			}
+
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				BaselinePassingFile:   baselineFile,
+			}
+			err := Exec(context.Background(), args)
+			if tc.expectError == "" {
+				if err != nil {
+					t.Errorf("Exec() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+				t.Errorf("Exec() expected error containing %q, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestExecBaselinePassingFileMissing(t *testing.T) {
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		BaselinePassingFile:   filepath.Join(t.TempDir(), "missing.txt"),
+	}
+	if err := Exec(context.Background(), args); err == nil || !strings.Contains(err.Error(), "failed to open baseline file") {
+		t.Errorf("Exec() expected a baseline file error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// BazelTestSuites is the root element of a Bazel test.xml file.
+type BazelTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []BazelTestSuite `xml:"testsuite"`
+}
+
+// BazelTestSuite represents a single suite in a Bazel test.xml file.
+type BazelTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []BazelTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out"`
+}
+
+// BazelTestCase represents a single test case in a Bazel test.xml file.
+// Bazel expects a status of "run" or "notrun" and a duration in whole seconds
+// alongside the JUnit time attribute.
+type BazelTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Status    string        `xml:"status,attr"`
+	Duration  int           `xml:"duration,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *BazelFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+// BazelFailure holds the failure details of a test case.
+type BazelFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// buildBazelXML converts the processed reports into Bazel's test.xml layout.
+func buildBazelXML(reports []fileReport, args Args) BazelTestSuites {
+	var root BazelTestSuites
+	var totalMS float64
+
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			errorCount, _ := parseErrorCount(suite.Errors)
+			bs := BazelTestSuite{Name: suite.Name, Errors: errorCount}
+			var suiteMS float64
+
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					duration, _ := strconv.ParseFloat(test.DurationMS, 64)
+					suiteMS += duration
+
+					tc := BazelTestCase{
+						Name:      test.Name,
+						ClassName: class.Name,
+						Status:    "run",
+						Duration:  int(duration / 1000),
+						Time:      formatSeconds(duration),
+					}
+					status := strings.ToUpper(test.Status)
+					switch {
+					case failStatuses[status]:
+						bs.Failures++
+						tc.Failure = &BazelFailure{
+							Message: firstLine(test.Exception),
+							Type:    "failure",
+							Body:    strings.TrimSpace(test.Exception),
+						}
+					case skipStatuses[status]:
+						bs.Skipped++
+						tc.Status = "notrun"
+						tc.Skipped = &struct{}{}
+					}
+					bs.Tests++
+					bs.TestCases = append(bs.TestCases, tc)
+				}
+			}
+
+			bs.Time = formatSeconds(suiteMS)
+			totalMS += suiteMS
+			root.Tests += bs.Tests
+			root.Failures += bs.Failures
+			root.Errors += bs.Errors
+			root.Suites = append(root.Suites, bs)
+		}
+	}
+
+	root.Time = formatSeconds(totalMS)
+	return root
+}
+
+// writeBazelXML writes the processed reports to filename in Bazel's test.xml layout.
+func writeBazelXML(filename string, reports []fileReport, args Args) error {
+	data, err := xml.MarshalIndent(buildBazelXML(reports, args), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Bazel test XML: %w", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write Bazel test XML to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// formatSeconds converts a duration in milliseconds to seconds with millisecond precision.
+func formatSeconds(ms float64) string {
+	return strconv.FormatFloat(ms/1000, 'f', 3, 64)
+}
+
+// firstLine returns the first non-empty line of s, trimmed of surrounding whitespace.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteBazelXML(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "test.xml")
+	reports := []fileReport{{Path: "testng-report.xml", Report: report}}
+	if err := writeBazelXML(output, reports, Args{}); err != nil {
+		t.Fatalf("writeBazelXML() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read Bazel XML: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("Bazel XML is missing the XML declaration")
+	}
+
+	// Decode into a schema-shaped struct independent of the writer's types
+	var got struct {
+		XMLName  xml.Name `xml:"testsuites"`
+		Tests    int      `xml:"tests,attr"`
+		Failures int      `xml:"failures,attr"`
+		Errors   int      `xml:"errors,attr"`
+		Suites   []struct {
+			Name      string `xml:"name,attr"`
+			Tests     int    `xml:"tests,attr"`
+			Failures  int    `xml:"failures,attr"`
+			Time      string `xml:"time,attr"`
+			TestCases []struct {
+				Name      string `xml:"name,attr"`
+				ClassName string `xml:"classname,attr"`
+				Status    string `xml:"status,attr"`
+				Duration  string `xml:"duration,attr"`
+				Time      string `xml:"time,attr"`
+				Failure   *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Failed to decode Bazel XML: %v", err)
+	}
+
+	if got.Tests != 3 || got.Failures != 1 || got.Errors != 0 {
+		t.Errorf("testsuites counts mismatch: tests=%d failures=%d errors=%d", got.Tests, got.Failures, got.Errors)
+	}
+	if len(got.Suites) != 1 {
+		t.Fatalf("Expected 1 testsuite, got %d", len(got.Suites))
+	}
+
+	suite := got.Suites[0]
+	if suite.Name != "Suite1" || suite.Tests != 3 || suite.Failures != 1 || suite.Time != "0.015" {
+		t.Errorf("testsuite mismatch: %+v", suite)
+	}
+
+	var names, statuses []string
+	for _, tc := range suite.TestCases {
+		names = append(names, tc.ClassName+"."+tc.Name)
+		statuses = append(statuses, tc.Status)
+		if tc.Duration == "" || tc.Time == "" {
+			t.Errorf("testcase %s is missing duration or time", tc.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"com.test.TestOne.test1", "com.test.TestOne.test2", "com.test.TestOne.setUp"}, names); diff != "" {
+		t.Errorf("testcase names mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"run", "run", "run"}, statuses); diff != "" {
+		t.Errorf("testcase statuses mismatch (-want +got):\n%s", diff)
+	}
+
+	failure := suite.TestCases[0].Failure
+	if failure == nil || failure.Message != "java.lang.AssertionError" {
+		t.Errorf("Expected failure with message java.lang.AssertionError, got %+v", failure)
+	}
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UngroupedBucket collects the tests whose description does not match
+// GroupByDescriptionPrefix.
+const UngroupedBucket = "(ungrouped)"
+
+// compileBucketPattern compiles the GroupByDescriptionPrefix regex, which
+// must have a capture group for the bucket name.
+func compileBucketPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GroupByDescriptionPrefix regex: %v", err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, errors.New("invalid GroupByDescriptionPrefix regex: it must have a capture group for the bucket name")
+	}
+	return re, nil
+}
+
+// bucketResults buckets the tests of report by the first capture group of re
+// matched against their description, e.g. "payments" for a description
+// starting with "[team:payments]" and the regex `^\[team:(\w+)\]`. Tests are
+// counted as in the run totals; class-level counts such as Flaky are not
+// bucketed.
+func bucketResults(report TestNGReport, re *regexp.Regexp, args Args) map[string]Results {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	buckets := make(map[string]Results)
+	for _, suite := range report.Suites {
+		for _, class := range suite.Classes {
+			tests := class.Tests
+			if args.CollapseParameterized {
+				tests = collapseParameterized(tests, failStatuses, skipStatuses)
+			}
+			for _, test := range tests {
+				bucket := UngroupedBucket
+				if match := re.FindStringSubmatch(test.Description); match != nil && match[1] != "" {
+					bucket = match[1]
+				}
+
+				// The warnings were logged when the class was aggregated
+				results := buckets[bucket]
+				countTest(&results, test, class.Name, failStatuses, skipStatuses, args.MaxTestDuration, noWarn)
+				buckets[bucket] = results
+			}
+		}
+	}
+	return buckets
+}
+
+// logBucketResults logs the totals of each description bucket.
+func logBucketResults(buckets map[string]Results, args Args) {
+	logrus.Infof("\nResults by Description Prefix:")
+	for _, name := range sortedSuiteNames(buckets) {
+		logrus.Infof("\n- %s: %s", name, formatResults(buckets[name], args))
+	}
+}
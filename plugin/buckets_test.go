@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessFilesGroupByDescriptionPrefix(t *testing.T) {
+	args := Args{GroupByDescriptionPrefix: `^\[team:(\w+)\]`}
+	agg := processFiles([]string{"../testdata/buckets/testng-team-descriptions.xml"}, args)
+
+	expected := map[string]Results{
+		"payments":      {Total: 2, Failures: 1, DurationMS: 15, MissingExceptions: 1},
+		"search":        {Total: 1, Skipped: 1},
+		UngroupedBucket: {Total: 1, DurationMS: 20},
+	}
+	if diff := cmp.Diff(expected, agg.Buckets); diff != "" {
+		t.Errorf("Buckets mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBucketTotalsMatchResults(t *testing.T) {
+	// The fixture has parameterized iterations and negative, NaN and
+	// implausible durations, which the run totals sanitize
+	args := Args{
+		GroupByDescriptionPrefix: `^\[team:(\w+)\]`,
+		CollapseParameterized:    true,
+		MaxTestDuration:          Duration(time.Minute),
+	}
+	agg := processFiles([]string{"../testdata/buckets/testng-invalid-durations.xml"}, args)
+	if agg.Results.Total != 4 || agg.Results.DurationMS != 60012 {
+		t.Fatalf("Unexpected run results: %+v", agg.Results)
+	}
+
+	var buckets Results
+	for _, res := range agg.Buckets {
+		buckets.add(res)
+	}
+	if buckets.Total != agg.Results.Total || buckets.DurationMS != agg.Results.DurationMS ||
+		buckets.InvalidDurations != agg.Results.InvalidDurations {
+		t.Errorf("Bucket totals %+v do not match the run results %+v", buckets, agg.Results)
+	}
+}
+
+func TestCompileBucketPattern(t *testing.T) {
+	if _, err := compileBucketPattern(`^\[team:(\w+)\]`); err != nil {
+		t.Errorf("compileBucketPattern() unexpected error: %v", err)
+	}
+	for _, pattern := range []string{`^\[team:\w+\]`, `^\[team:(\w+\]`} {
+		if _, err := compileBucketPattern(pattern); err == nil {
+			t.Errorf("compileBucketPattern(%q) expected error", pattern)
+		}
+	}
+}
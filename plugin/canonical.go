@@ -0,0 +1,37 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// buildCanonicalReport renders every test-method of the processed reports as
+// a "class.method status" line. Lines are sorted so the output does not
+// depend on the order of files, suites, classes or methods, which keeps it
+// stable for committing as an expected-results fixture.
+func buildCanonicalReport(reports []fileReport) []byte {
+	var lines []string
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					status := strings.ToUpper(strings.TrimSpace(test.Status))
+					lines = append(lines, class.Name+"."+test.Name+" "+status+"\n")
+				}
+			}
+		}
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, ""))
+}
+
+// writeCanonicalReport writes the canonical text report of the processed
+// reports to filename.
+func writeCanonicalReport(filename string, reports []fileReport) error {
+	if err := os.WriteFile(filename, buildCanonicalReport(reports), 0644); err != nil {
+		return fmt.Errorf("failed to write canonical report to %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExecCanonicalReportStable(t *testing.T) {
+	dir := t.TempDir()
+	ordered := `<testng-results>
+  <suite name="SuiteA"><test name="t"><class name="com.test.Alpha">
+    <test-method status="PASS" name="first" duration-ms="1"/>
+    <test-method status="fail" name="second" duration-ms="1"/>
+  </class></test></suite>
+  <suite name="SuiteB"><test name="t"><class name="com.test.Beta">
+    <test-method status="SKIP" name="only" duration-ms="0"/>
+  </class></test></suite>
+</testng-results>`
+	reordered := `<testng-results>
+  <suite name="SuiteB"><test name="t"><class name="com.test.Beta">
+    <test-method status="SKIP" name="only" duration-ms="0"/>
+  </class></test></suite>
+  <suite name="SuiteA"><test name="t"><class name="com.test.Alpha">
+    <test-method status="FAIL" name="second" duration-ms="1"/>
+    <test-method status="PASS" name="first" duration-ms="1"/>
+  </class></test></suite>
+</testng-results>`
+
+	run := func(name, content string) []byte {
+		report := filepath.Join(dir, name+".xml")
+		if err := os.WriteFile(report, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write report: %v", err)
+		}
+		output := filepath.Join(dir, name+".txt")
+		args := Args{
+			ReportFilenamePattern: report,
+			ThresholdMode:         ThresholdModeAbsolute,
+			CanonicalReportFile:   output,
+		}
+		if err := Exec(context.Background(), args); err != nil {
+			t.Fatalf("Exec() unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read canonical report: %v", err)
+		}
+		return data
+	}
+
+	expected := "com.test.Alpha.first PASS\ncom.test.Alpha.second FAIL\ncom.test.Beta.only SKIP\n"
+	first := run("ordered", ordered)
+	if diff := cmp.Diff(expected, string(first)); diff != "" {
+		t.Errorf("Canonical report mismatch (-want +got):\n%s", diff)
+	}
+	if second := run("reordered", reordered); string(first) != string(second) {
+		t.Errorf("Canonical report differs for reordered input:\n%q\n%q", first, second)
+	}
+}
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sortedSuiteNames returns the suite names of results in sorted order.
+func sortedSuiteNames(results map[string]Results) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// duplicateSuiteNames returns, in sorted order, the suite names that appear
+// in more than one file.
+func duplicateSuiteNames(suiteFiles map[string]int) []string {
+	var names []string
+	for name, files := range suiteFiles {
+		if files > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkMinMSPerTest returns an error listing the suites whose average
+// duration per test is below minMSPerTest. Suites without tests are ignored.
+func checkMinMSPerTest(suites map[string]Results, minMSPerTest float64) error {
+	if minMSPerTest <= 0 {
+		return nil
+	}
+
+	var fast []string
+	for _, name := range sortedSuiteNames(suites) {
+		res := suites[name]
+		if res.Total == 0 {
+			continue
+		}
+		if perTest := res.DurationMS / float64(res.Total); perTest < minMSPerTest {
+			fast = append(fast, fmt.Sprintf("%s (%.2f ms per test)", name, perTest))
+		}
+	}
+
+	if len(fast) > 0 {
+		return withFailReason(FailReasonMinMSPerTest, fmt.Errorf("\nsuites ran implausibly fast (below %.2f ms per test): %s", minMSPerTest, strings.Join(fast, ", ")))
+	}
+	return nil
+}
+
+// checkInvalidDurationRatio returns an error when the fraction of tests with
+// an invalid duration exceeds maxRatio, which suggests the report is corrupt
+// rather than a few tests being malformed.
+func checkInvalidDurationRatio(results Results, maxRatio float64) error {
+	if maxRatio <= 0 || results.Total == 0 {
+		return nil
+	}
+
+	if ratio := float64(results.InvalidDurations) / float64(results.Total); ratio > maxRatio {
+		return withFailReason(FailReasonInvalidDurationRatio, fmt.Errorf("\n%d of %d tests (%.2f) have an invalid duration, exceeding MaxInvalidDurationRatio (%.2f); the report is probably corrupt", results.InvalidDurations, results.Total, ratio, maxRatio))
+	}
+	return nil
+}
+
+// checkUniformCounts returns an error listing the test counts of every file
+// when the files do not all contain the same number of tests.
+func checkUniformCounts(fileTotals map[string]int) error {
+	counts := make(map[int]bool)
+	files := make([]string, 0, len(fileTotals))
+	for file, total := range fileTotals {
+		counts[total] = true
+		files = append(files, file)
+	}
+	if len(counts) <= 1 {
+		return nil
+	}
+
+	sort.Strings(files)
+	details := make([]string, 0, len(files))
+	for _, file := range files {
+		details = append(details, fmt.Sprintf("%s (%d tests)", file, fileTotals[file]))
+	}
+	return withFailReason(FailReasonUniformCounts, fmt.Errorf("\nbuild marked as failed as the files have differing test counts: %s", strings.Join(details, ", ")))
+}
@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCheckMinMSPerTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		suites    map[string]Results
+		min       float64
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name: "ImplausiblyFastSuite",
+			suites: map[string]Results{
+				"Fast": {Total: 100, DurationMS: 50},
+				"Slow": {Total: 10, DurationMS: 500},
+			},
+			min:       1,
+			expectErr: true,
+			errMsg:    "Fast (0.50 ms per test)",
+		},
+		{
+			name: "PlausibleSuites",
+			suites: map[string]Results{
+				"Slow": {Total: 10, DurationMS: 500},
+			},
+			min: 1,
+		},
+		{
+			name: "EmptySuiteIgnored",
+			suites: map[string]Results{
+				"Empty": {Total: 0, DurationMS: 0},
+			},
+			min: 1,
+		},
+		{
+			name: "CheckDisabled",
+			suites: map[string]Results{
+				"Fast": {Total: 100, DurationMS: 0},
+			},
+			min: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMinMSPerTest(tc.suites, tc.min)
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Errorf("checkMinMSPerTest() expected error %q but got %v", tc.errMsg, err)
+				}
+				if strings.Contains(err.Error(), "Slow") {
+					t.Errorf("checkMinMSPerTest() flagged a plausible suite: %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("checkMinMSPerTest() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDuplicateSuiteNames(t *testing.T) {
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/counts/*.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	found := false
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.InfoLevel && strings.Contains(entry.Message, "Suite 'CountedSuite' appears in 2 files") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a note about the duplicate suite name")
+	}
+
+	if names := duplicateSuiteNames(map[string]int{"Unique": 1, "B": 2, "A": 3}); strings.Join(names, ",") != "A,B" {
+		t.Errorf("duplicateSuiteNames() expected [A B], got %v", names)
+	}
+}
+
+func TestExecExpectUniformCounts(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "reports.txt")
+	reports := "../testdata/testng-report.xml\n../testdata/errors/testng-errors.xml\n"
+	if err := os.WriteFile(manifest, []byte(reports), 0644); err != nil {
+		t.Fatalf("Failed to write file list: %v", err)
+	}
+
+	args := Args{
+		FileList:            manifest,
+		ThresholdMode:       ThresholdModeAbsolute,
+		ExpectUniformCounts: true,
+	}
+	err := Exec(context.Background(), args)
+	expected := "differing test counts: ../testdata/errors/testng-errors.xml (2 tests), ../testdata/testng-report.xml (3 tests)"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("Exec() expected error containing %q, got %v", expected, err)
+	}
+
+	if err := checkUniformCounts(map[string]int{"linux.xml": 3, "windows.xml": 3}); err != nil {
+		t.Errorf("checkUniformCounts() unexpected error for uniform counts: %v", err)
+	}
+}
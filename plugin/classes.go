@@ -0,0 +1,42 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// classExecuted reports whether class has at least one test method, other
+// than a configuration method, that was not skipped.
+func classExecuted(class Class, skipStatuses map[string]bool) bool {
+	for _, test := range class.Tests {
+		if !test.IsConfig && !skipStatuses[strings.ToUpper(test.Status)] {
+			return true
+		}
+	}
+	return false
+}
+
+// executedClasses returns the names of the classes in report that executed
+// at least one test.
+func executedClasses(report TestNGReport, args Args) []string {
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	var names []string
+	for _, suite := range report.Suites {
+		for _, class := range suite.Classes {
+			if classExecuted(class, skipStatuses) {
+				names = append(names, class.Name)
+			}
+		}
+	}
+	return names
+}
+
+// checkMinClasses returns an error when fewer than minClasses distinct
+// classes executed a test.
+func checkMinClasses(classes map[string]bool, minClasses int) error {
+	if minClasses > 0 && len(classes) < minClasses {
+		return withFailReason(FailReasonMinClasses, fmt.Errorf("\nbuild marked as failed as only %d distinct classes executed tests, below MinClasses (%d)", len(classes), minClasses))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestExecutedClasses(t *testing.T) {
+	report, err := parseReport("../testdata/skips/testng-skipped-classes.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"com.test.Mixed"}, executedClasses(report, Args{})); diff != "" {
+		t.Errorf("executedClasses() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecMinClasses(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "reports.txt")
+	reports := "../testdata/testng-report.xml\n../testdata/skips/testng-skipped-classes.xml\n"
+	if err := os.WriteFile(manifest, []byte(reports), 0644); err != nil {
+		t.Fatalf("Failed to write file list: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		minClasses  int
+		expectError bool
+	}{
+		// The reports exercise com.test.TestOne and com.test.Mixed; the
+		// entirely skipped com.test.Disabled does not count
+		{name: "Enough", minClasses: 2, expectError: false},
+		{name: "TooFew", minClasses: 3, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				FileList:      manifest,
+				ThresholdMode: ThresholdModeAbsolute,
+				MinClasses:    tc.minClasses,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestEmptyClasses(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	results, err := processFile("../testdata/skips/testng-empty-classes.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.EmptyClasses != 2 || results.Total != 1 {
+		t.Errorf("Expected 2 empty classes and 1 test, got %+v", results)
+	}
+
+	var emptyClasses []string
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "Class with no executed methods:") {
+			emptyClasses = append(emptyClasses, entry.Message)
+		}
+	}
+	expected := []string{
+		"\nClass with no executed methods: com.test.Deselected",
+		"\nClass with no executed methods: com.test.AlsoDeselected",
+	}
+	if diff := cmp.Diff(expected, emptyClasses); diff != "" {
+		t.Errorf("Empty class log mismatch (-want +got):\n%s", diff)
+	}
+
+	args := Args{ThresholdMode: ThresholdModeAbsolute}
+	if err := validateThresholds(results, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+	args.FailOnEmptyClasses = true
+	if err := validateThresholds(results, args); err == nil || !strings.Contains(err.Error(), "2 classes have no executed methods") {
+		t.Errorf("validateThresholds() expected empty classes error, got %v", err)
+	}
+}
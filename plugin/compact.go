@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// compactVersion is the first byte of the compact encoding. It changes
+// whenever the encoded fields change, so stale caches are rejected.
+const compactVersion = 3
+
+// compactCounts returns pointers to the counters of r in encoding order.
+func compactCounts(r *Results) []*int {
+	return []*int{
+		&r.Total, &r.Failures, &r.Skipped, &r.Errors,
+		&r.ConfigSkips, &r.AssertionFailures, &r.ErrorFailures,
+		&r.FullySkippedClasses, &r.EmptyClasses, &r.MissingExceptions,
+		&r.Flaky, &r.Assertions, &r.AssertionsReported, &r.Unknown,
+		&r.InvalidDurations,
+	}
+}
+
+// MarshalCompact encodes results as a version byte, each counter as a
+// varint and the duration as big-endian float64 bits. The encoding is
+// deterministic, so equal results always encode to equal bytes.
+func MarshalCompact(results Results) []byte {
+	data := []byte{compactVersion}
+	for _, count := range compactCounts(&results) {
+		data = binary.AppendVarint(data, int64(*count))
+	}
+	return binary.BigEndian.AppendUint64(data, math.Float64bits(results.DurationMS))
+}
+
+// UnmarshalCompact decodes results encoded by MarshalCompact.
+func UnmarshalCompact(data []byte) (Results, error) {
+	if len(data) == 0 {
+		return Results{}, errors.New("empty compact results")
+	}
+	if data[0] != compactVersion {
+		return Results{}, fmt.Errorf("unsupported compact results version %d", data[0])
+	}
+	data = data[1:]
+
+	var results Results
+	for _, count := range compactCounts(&results) {
+		value, n := binary.Varint(data)
+		if n <= 0 {
+			return Results{}, errors.New("truncated compact results")
+		}
+		*count = int(value)
+		data = data[n:]
+	}
+
+	if len(data) != 8 {
+		return Results{}, errors.New("malformed compact results duration")
+	}
+	results.DurationMS = math.Float64frombits(binary.BigEndian.Uint64(data))
+	return results, nil
+}
+
+// cacheKey identifies the input of a run: it hashes the configuration and the
+// path, size and modification time of each report file, so changed reports or
+// settings miss the cache.
+func cacheKey(files []string, args Args) ([]byte, error) {
+	config, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write(config)
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(hash, "\x00%s\x00%d\x00%d", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return hash.Sum(nil), nil
+}
+
+// cacheCovers reports whether the cached totals are all a run needs. Outputs
+// and checks built from the parsed reports, or from per-suite, per-class or
+// per-file results, need the reports processed again, as do warnings, which
+// are only logged while processing.
+func cacheCovers(args Args) bool {
+	return !needsReports(args) && args.AggregateBy == "" && args.GroupByDescriptionPrefix == "" &&
+		args.ExpectedCounts == "" && args.MinMSPerTest == 0 && !args.ExpectUniformCounts && args.MinClasses == 0 &&
+		!args.FailOnWarnings && args.SlackMessageFile == "" && os.Getenv("GITHUB_STEP_SUMMARY") == ""
+}
+
+// readCacheFile returns the results cached in filename for key. It reports
+// false when the file does not exist or was written for another key.
+func readCacheFile(filename string, key []byte) (Results, bool, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return Results{}, false, nil
+	}
+	if err != nil {
+		return Results{}, false, fmt.Errorf("failed to read cache file %s: %w", filename, err)
+	}
+
+	if len(data) < sha256.Size {
+		return Results{}, false, fmt.Errorf("failed to decode cache file %s: truncated cache key", filename)
+	}
+	if !bytes.Equal(data[:sha256.Size], key) {
+		return Results{}, false, nil
+	}
+
+	results, err := UnmarshalCompact(data[sha256.Size:])
+	if err != nil {
+		return Results{}, false, fmt.Errorf("failed to decode cache file %s: %w", filename, err)
+	}
+	return results, true, nil
+}
+
+// writeCacheFile writes key followed by results in the compact encoding to
+// filename.
+func writeCacheFile(filename string, key []byte, results Results) error {
+	data := append(append([]byte{}, key...), MarshalCompact(results)...)
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	results := Results{
+		Total: 1200, Failures: 3, Skipped: 7, Errors: 1, DurationMS: 12345.678,
+		ConfigSkips: 2, AssertionFailures: 2, ErrorFailures: 1, FullySkippedClasses: 1,
+		EmptyClasses: 4, MissingExceptions: 1, Flaky: 5, Assertions: 3000, AssertionsReported: 900,
+	}
+
+	data := MarshalCompact(results)
+	if !bytes.Equal(data, MarshalCompact(results)) {
+		t.Errorf("MarshalCompact() is not deterministic")
+	}
+
+	got, err := UnmarshalCompact(data)
+	if err != nil {
+		t.Fatalf("UnmarshalCompact() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(results, got); diff != "" {
+		t.Errorf("Round trip mismatch (-want +got):\n%s", diff)
+	}
+
+	for name, bad := range map[string][]byte{
+		"Empty":        nil,
+		"Version":      append([]byte{compactVersion + 1}, data[1:]...),
+		"Truncated":    data[:len(data)-3],
+		"TrailingByte": append(append([]byte(nil), data...), 0),
+	} {
+		if _, err := UnmarshalCompact(bad); err == nil {
+			t.Errorf("UnmarshalCompact() expected an error for %s data", name)
+		}
+	}
+}
+
+func TestExecCacheFile(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("../testdata/testng-report.xml")
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	report := filepath.Join(dir, "testng-results.xml")
+	if err := os.WriteFile(report, data, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	args := Args{
+		ReportFilenamePattern: report,
+		ThresholdMode:         ThresholdModeAbsolute,
+		CacheFile:             filepath.Join(dir, "results.cache"),
+	}
+	restored := func() bool {
+		for _, entry := range hook.Entries {
+			if strings.HasPrefix(entry.Message, "\nRestored results from cache file") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if restored() {
+		t.Fatal("Expected the first run to process the reports")
+	}
+	key, err := cacheKey([]string{report}, args)
+	if err != nil {
+		t.Fatalf("cacheKey() unexpected error: %v", err)
+	}
+	cached, ok, err := readCacheFile(args.CacheFile, key)
+	if err != nil || !ok {
+		t.Fatalf("readCacheFile() = %v, %v", ok, err)
+	}
+	if cached.Total != 3 || cached.Failures != 1 || cached.DurationMS != 15 {
+		t.Errorf("Unexpected cached results: %+v", cached)
+	}
+
+	// An unchanged report hits the cache
+	hook.Entries = nil
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() unexpected error on a cache hit: %v", err)
+	}
+	if !restored() {
+		t.Error("Expected the second run to restore the cached results")
+	}
+
+	// Different settings miss the cache and thresholds use the new results
+	hook.Entries = nil
+	args.FailureOnFailedTestConfig = true
+	if err := Exec(context.Background(), args); err == nil {
+		t.Error("Exec() expected a threshold error")
+	}
+	if restored() {
+		t.Error("Expected changed settings to miss the cache")
+	}
+
+	// A modified report misses the cache
+	hook.Entries = nil
+	args.FailureOnFailedTestConfig = false
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(report, later, later); err != nil {
+		t.Fatalf("Failed to touch report: %v", err)
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() unexpected error: %v", err)
+	}
+	if restored() {
+		t.Error("Expected a modified report to miss the cache")
+	}
+}
+
+func TestExecCacheFileKeepsFailingBuildFailed(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/flaky/testng-flaky.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		CacheFile:             filepath.Join(t.TempDir(), "results.cache"),
+		MaxFlakyRate:          10,
+	}
+	for run := 1; run <= 2; run++ {
+		err := Exec(context.Background(), args)
+		if reason, _ := FailReasonOf(err); reason != FailReasonFlakyRate {
+			t.Errorf("Run %d: expected a %s error, got %v", run, FailReasonFlakyRate, err)
+		}
+	}
+
+	var restored bool
+	for _, entry := range hook.Entries {
+		restored = restored || strings.HasPrefix(entry.Message, "\nRestored results from cache file")
+	}
+	if !restored {
+		t.Error("Expected the second run to restore the cached results")
+	}
+}
@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReportDiff categorizes tests by how their outcome changed between a
+// "before" and an "after" set of reports. Tests are identified by class.method.
+type ReportDiff struct {
+	NewlyFailing []string
+	NewlyPassing []string
+	StillFailing []string
+}
+
+// testOutcomes maps each test of reports, keyed by class.method, to whether
+// it failed. A test that appears more than once is failed if any run failed.
+func testOutcomes(reports []TestNGReport, args Args) map[string]bool {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	outcomes := make(map[string]bool)
+	for _, report := range reports {
+		for _, suite := range report.Suites {
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					status := strings.ToUpper(test.Status)
+					if skipStatuses[status] {
+						continue
+					}
+					key := class.Name + "." + test.Name
+					outcomes[key] = outcomes[key] || failStatuses[status]
+				}
+			}
+		}
+	}
+	return outcomes
+}
+
+// diffOutcomes compares the outcomes of two report sets. Tests that only
+// exist in the after set and fail are reported as newly failing.
+func diffOutcomes(before, after map[string]bool) ReportDiff {
+	var diff ReportDiff
+	for name, failed := range after {
+		failedBefore, ok := before[name]
+		switch {
+		case failed && failedBefore:
+			diff.StillFailing = append(diff.StillFailing, name)
+		case failed:
+			diff.NewlyFailing = append(diff.NewlyFailing, name)
+		case ok && failedBefore:
+			diff.NewlyPassing = append(diff.NewlyPassing, name)
+		}
+	}
+
+	sort.Strings(diff.NewlyFailing)
+	sort.Strings(diff.NewlyPassing)
+	sort.Strings(diff.StillFailing)
+	return diff
+}
+
+// parseReports parses every report matching pattern, skipping files that
+// cannot be parsed. The reports come from an earlier run, so they are not
+// checked for staleness.
+func parseReports(pattern string, args Args) ([]TestNGReport, error) {
+	args.BuildStartEnv = ""
+
+	files, err := locateFiles(pattern, Args{})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no TestNG XML report files found matching the pattern: %s", pattern)
+	}
+
+	var reports []TestNGReport
+	for _, f := range files {
+		report, err := parseReport(f, args)
+		if err != nil {
+			logrus.Warn(fmt.Errorf("failed to process file %s: %w", f, err))
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// compareReports parses the reports matching args.ComparePattern and diffs
+// them against the processed reports.
+func compareReports(reports []fileReport, args Args) (ReportDiff, error) {
+	before, err := parseReports(args.ComparePattern, args)
+	if err != nil {
+		return ReportDiff{}, fmt.Errorf("failed to load comparison reports: %w", err)
+	}
+
+	after := make([]TestNGReport, 0, len(reports))
+	for _, fr := range reports {
+		after = append(after, fr.Report)
+	}
+
+	return diffOutcomes(testOutcomes(before, args), testOutcomes(after, args)), nil
+}
+
+// logReportDiff logs the categorized tests of diff, listing at most maxNames
+// names per category.
+func logReportDiff(diff ReportDiff, maxNames int) {
+	logrus.Infof("\n===============================================")
+	logrus.Infof("\nComparison with previous reports:")
+	logrus.Infof("\nNewly Failing (%d): %s", len(diff.NewlyFailing), formatTestNames(diff.NewlyFailing, maxNames))
+	logrus.Infof("\nNewly Passing (%d): %s", len(diff.NewlyPassing), formatTestNames(diff.NewlyPassing, maxNames))
+	logrus.Infof("\nStill Failing (%d): %s", len(diff.StillFailing), formatTestNames(diff.StillFailing, maxNames))
+	logrus.Infof("\n===============================================")
+}
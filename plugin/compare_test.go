@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompareReports(t *testing.T) {
+	report, err := parseReport("../testdata/compare/after/testng-results.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	args := Args{ComparePattern: "../testdata/compare/before/*.xml"}
+	diff, err := compareReports([]fileReport{{Report: report}}, args)
+	if err != nil {
+		t.Fatalf("compareReports() unexpected error: %v", err)
+	}
+
+	expected := ReportDiff{
+		NewlyFailing: []string{"com.test.Compare.test2"},
+		NewlyPassing: []string{"com.test.Compare.test1"},
+		StillFailing: []string{"com.test.Compare.test3"},
+	}
+	if diff := cmp.Diff(expected, diff); diff != "" {
+		t.Errorf("compareReports() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompareReportsMissingBefore(t *testing.T) {
+	args := Args{ComparePattern: "../testdata/compare/missing/*.xml"}
+	if _, err := compareReports(nil, args); err == nil {
+		t.Errorf("compareReports() expected error for a pattern without matches")
+	}
+}
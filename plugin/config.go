@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadArgs loads the plugin arguments from the environment. When
+// PLUGIN_CONFIG_FILE is set, the JSON or YAML file is applied over the
+// defaults, and environment variables that are set override file values.
+func LoadArgs() (Args, error) {
+	var args Args
+	if err := envconfig.Process("", &args); err != nil {
+		return Args{}, err
+	}
+
+	if args.ConfigFile == "" {
+		return args, nil
+	}
+
+	if err := loadConfigFile(args.ConfigFile, &args); err != nil {
+		return Args{}, err
+	}
+	return args, nil
+}
+
+// loadConfigFile reads a JSON or YAML config file into args. Keys use the
+// same names as the step settings, e.g. report_filename_pattern. Settings
+// whose environment variable is set are left alone, so the environment wins.
+func loadConfigFile(filename string, args *Args) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", filename, err)
+	}
+
+	// YAML is a superset of JSON, so a single decoder handles both formats
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
+	fields := argsFieldsByEnv(args)
+	for key, value := range settings {
+		name := "PLUGIN_" + strings.ToUpper(key)
+		field, ok := fields[name]
+		if !ok {
+			logrus.Warnf("Ignoring unknown setting '%s' in config file %s", key, filename)
+			continue
+		}
+		if _, set := os.LookupEnv(name); set {
+			continue
+		}
+		str, err := settingString(value)
+		if err == nil {
+			err = setField(field, str)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for setting '%s' in config file %s: %w", key, filename, err)
+		}
+	}
+
+	return nil
+}
+
+// settingString converts a decoded config file value to the string form the
+// matching environment variable would hold. Lists of scalars are joined with
+// commas, e.g. for fail_statuses, and lists or maps holding maps are encoded
+// as JSON, e.g. for threshold_rules.
+func settingString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				data, err := json.Marshal(v)
+				return string(data), err
+			}
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// argsFieldsByEnv maps the envconfig name of each Args field to its settable value.
+func argsFieldsByEnv(args *Args) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	v := reflect.ValueOf(args).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("envconfig"); name != "" {
+			fields[name] = v.Field(i)
+		}
+	}
+	return fields
+}
+
+// setField parses value according to the kind of field and assigns it.
+func setField(field reflect.Value, value string) error {
+	if decoder, ok := field.Addr().Interface().(envconfig.Decoder); ok {
+		return decoder.Decode(value)
+	}
+
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Kind())
+	}
+	return nil
+}
+
+// effectiveConfig returns every Args field keyed by its name, for logging the
+// configuration in effect. Fields tagged as secret are masked when set, as is
+// any password embedded in ReportURL.
+func effectiveConfig(args Args) logrus.Fields {
+	fields := make(logrus.Fields)
+	v := reflect.ValueOf(args)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("secret") == "true" && !v.Field(i).IsZero() {
+			fields[t.Field(i).Name] = "[REDACTED]"
+			continue
+		}
+		fields[t.Field(i).Name] = v.Field(i).Interface()
+	}
+	fields["ReportURL"] = redactedURL(args.ReportURL)
+	return fields
+}
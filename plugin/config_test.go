@@ -0,0 +1,180 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLoadArgsWithConfigFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		config string
+	}{
+		{
+			name: "YAML",
+			file: "config.yml",
+			config: `report_filename_pattern: "reports/*.xml"
+failed_fails: 3
+failed_skips: 2
+threshold_mode: percentage
+failure_on_failed_test_config: true
+wait_for_reports: 30s
+`,
+		},
+		{
+			name:   "JSON",
+			file:   "config.json",
+			config: `{"report_filename_pattern": "reports/*.xml", "failed_fails": 3, "failed_skips": 2, "threshold_mode": "percentage", "failure_on_failed_test_config": true, "wait_for_reports": "30s"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(configFile, []byte(tc.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			t.Setenv("PLUGIN_CONFIG_FILE", configFile)
+			t.Setenv("PLUGIN_FAILED_SKIPS", "7")
+
+			args, err := LoadArgs()
+			if err != nil {
+				t.Fatalf("LoadArgs() unexpected error: %v", err)
+			}
+
+			// Values only present in the file are loaded
+			if args.ReportFilenamePattern != "reports/*.xml" || args.FailedFails != 3 ||
+				args.ThresholdMode != ThresholdModePercentage || !args.FailureOnFailedTestConfig ||
+				args.WaitForReports != 30*time.Second {
+				t.Errorf("LoadArgs() did not load values from the config file: %+v", args)
+			}
+			// Environment variables override the file
+			if args.FailedSkips != 7 {
+				t.Errorf("Expected PLUGIN_FAILED_SKIPS to override the config file, got %d", args.FailedSkips)
+			}
+			if args.ConfigFile != configFile {
+				t.Errorf("Expected ConfigFile %q, got %q", configFile, args.ConfigFile)
+			}
+		})
+	}
+}
+
+func TestLoadArgsWithInvalidConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(configFile, []byte("failed_fails: many\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("PLUGIN_CONFIG_FILE", configFile)
+
+	if _, err := LoadArgs(); err == nil {
+		t.Errorf("LoadArgs() expected error for invalid setting value")
+	}
+}
+
+func TestLoadArgsConfigFileLists(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	config := `fail_statuses:
+  - FAIL
+  - BROKEN
+threshold_rules:
+  - metric: failures
+    mode: absolute
+    limit: 5
+  - metric: skipped
+    mode: percentage
+    limit: 2
+`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("PLUGIN_CONFIG_FILE", configFile)
+
+	args, err := LoadArgs()
+	if err != nil {
+		t.Fatalf("LoadArgs() unexpected error: %v", err)
+	}
+	if args.FailStatuses != "FAIL,BROKEN" {
+		t.Errorf("Expected FailStatuses %q, got %q", "FAIL,BROKEN", args.FailStatuses)
+	}
+
+	rules, err := parseThresholdRules(args.ThresholdRules)
+	if err != nil {
+		t.Fatalf("parseThresholdRules(%q) unexpected error: %v", args.ThresholdRules, err)
+	}
+	expected := []ThresholdRule{
+		{Metric: MetricFailures, Mode: ThresholdModeAbsolute, Limit: 5},
+		{Metric: MetricSkipped, Mode: ThresholdModePercentage, Limit: 2},
+	}
+	if diff := cmp.Diff(expected, rules); diff != "" {
+		t.Errorf("Threshold rules mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecLogsEffectiveConfig(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logrus.StandardLogger().Hooks {
+		hooks[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	t.Cleanup(func() {
+		logrus.SetLevel(logrus.InfoLevel)
+		logrus.StandardLogger().ReplaceHooks(hooks)
+	})
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModePercentage,
+		FailedFails:           50,
+		MaxNamesLogged:        20,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	for _, entry := range hook.Entries {
+		if entry.Level != logrus.DebugLevel || entry.Message != "Effective configuration" {
+			continue
+		}
+		if entry.Fields["ThresholdMode"] != ThresholdModePercentage || entry.Fields["FailedFails"] != 50 ||
+			entry.Fields["MaxNamesLogged"] != 20 || entry.Fields["ReportFilenamePattern"] != "../testdata/testng-report.xml" {
+			t.Errorf("Effective configuration fields mismatch: %+v", entry.Fields)
+		}
+		if _, ok := entry.Fields["FailOnWarnings"]; !ok {
+			t.Errorf("Expected every Args field to be logged, missing FailOnWarnings")
+		}
+		return
+	}
+	t.Errorf("Expected the effective configuration to be logged at debug level")
+}
+
+func TestLoadArgsConfigFileOverridesDefaults(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	config := "max_names_logged: 5\nroot_element: foo\ntimeout: 5s\n"
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("PLUGIN_CONFIG_FILE", configFile)
+
+	args, err := LoadArgs()
+	if err != nil {
+		t.Fatalf("LoadArgs() unexpected error: %v", err)
+	}
+	if args.MaxNamesLogged != 5 || args.RootElement != "foo" || args.Timeout != 5*time.Second {
+		t.Errorf("Expected the config file to override defaults, got MaxNamesLogged %d, RootElement %q, Timeout %s",
+			args.MaxNamesLogged, args.RootElement, args.Timeout)
+	}
+	// Fields the file does not set keep their defaults
+	if args.MaxTestNames != 1000 {
+		t.Errorf("Expected the default MaxTestNames, got %d", args.MaxTestNames)
+	}
+}
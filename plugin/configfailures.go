@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// configFailurePolicyEffects describes what each TestNG configfailurepolicy
+// means for the tests following a failed configuration method.
+var configFailurePolicyEffects = map[string]string{
+	"skip":     "subsequent tests were skipped",
+	"continue": "subsequent tests still ran",
+}
+
+// configFailures returns the failed configuration methods of suite, annotated
+// with the config failure policy when the report records one, e.g.
+// "com.test.Orders.setUp (config-failure-policy: skip; subsequent tests were skipped)".
+// A policy on the method takes precedence over the suite's.
+func configFailures(suite Suite, args Args) []string {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+
+	var failures []string
+	for _, class := range suite.Classes {
+		for _, test := range class.Tests {
+			if !test.IsConfig || !failStatuses[strings.ToUpper(test.Status)] {
+				continue
+			}
+
+			name := class.Name + "." + test.Name
+			policy := test.ConfigFailurePolicy
+			if policy == "" {
+				policy = suite.ConfigFailurePolicy
+			}
+			if policy == "" {
+				failures = append(failures, name)
+				continue
+			}
+
+			note := "config-failure-policy: " + policy
+			if effect, ok := configFailurePolicyEffects[strings.ToLower(policy)]; ok {
+				note += "; " + effect
+			}
+			failures = append(failures, fmt.Sprintf("%s (%s)", name, note))
+		}
+	}
+	return failures
+}
+
+// logConfigFailures logs the failed configuration methods of suite.
+func logConfigFailures(suite Suite, args Args) {
+	failures := configFailures(suite, args)
+	if len(failures) > 0 {
+		logrus.Infof("\nFailed configuration methods: %s", formatTestNames(failures, args.MaxNamesLogged))
+	}
+}
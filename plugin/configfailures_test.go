@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigFailures(t *testing.T) {
+	report, err := parseReport("../testdata/config/testng-config-failure-policy.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	// The suite's policy applies unless the method records its own
+	expected := []string{
+		"com.test.Database.setUpClass (config-failure-policy: skip; subsequent tests were skipped)",
+		"com.test.Cache.setUp (config-failure-policy: continue; subsequent tests still ran)",
+	}
+	if diff := cmp.Diff(expected, configFailures(report.Suites[0], Args{})); diff != "" {
+		t.Errorf("configFailures() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without a policy only the method is named
+	report.Suites[0].ConfigFailurePolicy = ""
+	report.Suites[0].Classes[1].Tests[0].ConfigFailurePolicy = ""
+	expected = []string{"com.test.Database.setUpClass", "com.test.Cache.setUp"}
+	if diff := cmp.Diff(expected, configFailures(report.Suites[0], Args{})); diff != "" {
+		t.Errorf("configFailures() without policy mismatch (-want +got):\n%s", diff)
+	}
+}
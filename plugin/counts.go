@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"strconv"
+)
+
+// results converts the reported counts into Results. It reports false when
+// the total is absent or invalid. A missing skipped count is computed as
+// total - passed - failed.
+func (c ReportedCounts) results() (Results, bool) {
+	total, err := strconv.Atoi(c.ReportedTotal)
+	if err != nil {
+		return Results{}, false
+	}
+	failed, _ := strconv.Atoi(c.ReportedFailed)
+
+	skipped, err := strconv.Atoi(c.ReportedSkipped)
+	if err != nil {
+		skipped = 0
+		if passed, err := strconv.Atoi(c.ReportedPassed); err == nil {
+			skipped = total - passed - failed
+		}
+	}
+	if skipped < 0 {
+		skipped = 0
+	}
+
+	return Results{Total: total, Failures: failed, Skipped: skipped}, true
+}
+
+// applyReportedCounts overrides the counted totals of results with the
+// reported counts when they are present. Duration and errors are kept.
+func applyReportedCounts(results Results, counts ReportedCounts) Results {
+	reported, ok := counts.results()
+	if !ok {
+		return results
+	}
+
+	results.Total = reported.Total
+	results.Failures = reported.Failures
+	results.Skipped = reported.Skipped
+	return results
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessFileWithReportedCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		args     Args
+		expected Results
+	}{
+		{
+			name:     "RootCountsIgnoredByDefault",
+			filePath: "../testdata/counts/testng-root-counts.xml",
+			args:     Args{},
+			expected: Results{Total: 2, Failures: 1, DurationMS: 10, MissingExceptions: 1},
+		},
+		{
+			name:     "RootCountsWithComputedSkips",
+			filePath: "../testdata/counts/testng-root-counts.xml",
+			args:     Args{PreferReportedCounts: true},
+			expected: Results{Total: 5, Failures: 1, Skipped: 1, DurationMS: 10, MissingExceptions: 1},
+		},
+		{
+			name:     "SuiteCounts",
+			filePath: "../testdata/counts/testng-suite-counts.xml",
+			args:     Args{PreferReportedCounts: true},
+			expected: Results{Total: 4, Failures: 1, Skipped: 1, DurationMS: 5},
+		},
+		{
+			name:     "FallbackToMethodCounting",
+			filePath: "../testdata/testng-report.xml",
+			args:     Args{PreferReportedCounts: true},
+			expected: Results{Total: 3, Failures: 1, DurationMS: 15, AssertionFailures: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := processFile(tc.filePath, tc.args)
+			if err != nil {
+				t.Fatalf("processFile() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, result); diff != "" {
+				t.Errorf("processFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// csvHeader lists the columns of the CSV output.
+var csvHeader = []string{"suite", "class", "test", "status", "duration_ms", "exception"}
+
+// writeCSV writes one row per test-method of the processed reports to filename.
+func writeCSV(filename string, reports []fileReport) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err := w.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					row := []string{suite.Name, class.Name, test.Name, test.Status, test.DurationMS, strings.TrimSpace(test.Exception)}
+					if err := w.Write(row); err != nil {
+						return fmt.Errorf("failed to write CSV row for test %s: %w", test.Name, err)
+					}
+				}
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteCSV(t *testing.T) {
+	report := TestNGReport{
+		Suites: []Suite{
+			{
+				Name: "Suite1",
+				Classes: []Class{
+					{
+						Name: "com.test.TestOne",
+						Tests: []Test{
+							{Name: "test1", Status: "PASS", DurationMS: "10"},
+							{Name: "test2", Status: "FAIL", DurationMS: "20", Exception: "\n  java.lang.AssertionError: expected [1, 2] but found [3]\n  at com.test.TestOne.test2\n"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	output := filepath.Join(t.TempDir(), "tests.csv")
+	if err := writeCSV(output, []fileReport{{Report: report}}); err != nil {
+		t.Fatalf("writeCSV() unexpected error: %v", err)
+	}
+
+	file, err := os.Open(output)
+	if err != nil {
+		t.Fatalf("Failed to open CSV file: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV file: %v", err)
+	}
+
+	expected := [][]string{
+		{"suite", "class", "test", "status", "duration_ms", "exception"},
+		{"Suite1", "com.test.TestOne", "test1", "PASS", "10", ""},
+		{"Suite1", "com.test.TestOne", "test2", "FAIL", "20", "java.lang.AssertionError: expected [1, 2] but found [3]\n  at com.test.TestOne.test2"},
+	}
+	if diff := cmp.Diff(expected, rows); diff != "" {
+		t.Errorf("CSV rows mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// failedGroups returns the groups of suite with at least one failed method.
+func failedGroups(suite Suite, args Args) map[string]bool {
+	failed := make(map[string]bool)
+	for _, outcome := range groupOutcomes(suite, args) {
+		if outcome.Failures > 0 {
+			failed[outcome.Name] = true
+		}
+	}
+	return failed
+}
+
+// dependencySkips returns the skipped tests of suite that depend on a failed
+// group, annotated with the failed groups, e.g.
+// "com.test.Orders.test3 (depends on failed group: database)".
+func dependencySkips(suite Suite, args Args) []string {
+	failed := failedGroups(suite, args)
+	if len(failed) == 0 {
+		return nil
+	}
+
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+	var skips []string
+	for _, class := range suite.Classes {
+		for _, test := range class.Tests {
+			if !skipStatuses[strings.ToUpper(test.Status)] {
+				continue
+			}
+
+			var causes []string
+			for _, group := range strings.Split(test.DependsOnGroups, ",") {
+				if group = strings.TrimSpace(group); failed[group] {
+					causes = append(causes, group)
+				}
+			}
+			if len(causes) > 0 {
+				skips = append(skips, fmt.Sprintf("%s.%s (depends on failed group: %s)", class.Name, test.Name, strings.Join(causes, ", ")))
+			}
+		}
+	}
+	return skips
+}
+
+// logDependencySkips logs the skipped tests of suite caused by a failed group
+// dependency.
+func logDependencySkips(suite Suite, args Args) {
+	skips := dependencySkips(suite, args)
+	if len(skips) > 0 {
+		logrus.Infof("\nDependency-induced skips: %s", formatTestNames(skips, args.MaxNamesLogged))
+	}
+}
@@ -0,0 +1,20 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDependencySkips(t *testing.T) {
+	report, err := parseReport("../testdata/dependencies/testng-group-dependency.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	// Only the skip depending on the failed database group is annotated
+	expected := []string{"com.test.Orders.create (depends on failed group: database)"}
+	if diff := cmp.Diff(expected, dependencySkips(report.Suites[0], Args{})); diff != "" {
+		t.Errorf("dependencySkips() mismatch (-want +got):\n%s", diff)
+	}
+}
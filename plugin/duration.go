@@ -0,0 +1,37 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Duration is a duration setting that accepts Go duration syntax, e.g.
+// "1m30s", or a plain number of milliseconds, e.g. "90000".
+type Duration time.Duration
+
+// Decode parses value as a number of milliseconds or a Go duration. It is
+// called by envconfig and when loading config files.
+func (d *Duration) Decode(value string) error {
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		*d = Duration(ms * float64(time.Millisecond))
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q. Use milliseconds (e.g. 90000) or a duration (e.g. 1m30s)", value)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Milliseconds returns the duration in milliseconds.
+func (d Duration) Milliseconds() float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// String formats the duration in Go duration syntax.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
@@ -0,0 +1,212 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDurationDecode(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{value: "90000", expected: 90 * time.Second},
+		{value: "1m30s", expected: 90 * time.Second},
+		{value: "1.5", expected: 1500 * time.Microsecond},
+		{value: "90 seconds", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			var d Duration
+			err := d.Decode(tc.value)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Decode(%q) expected error", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode(%q) unexpected error: %v", tc.value, err)
+			}
+			if time.Duration(d) != tc.expected {
+				t.Errorf("Decode(%q) expected %s, got %s", tc.value, tc.expected, time.Duration(d))
+			}
+		})
+	}
+}
+
+func TestLoadArgsMaxDuration(t *testing.T) {
+	// Environment variables and config files accept both formats
+	t.Setenv("PLUGIN_MAX_DURATION", "90000")
+	args, err := LoadArgs()
+	if err != nil {
+		t.Fatalf("LoadArgs() unexpected error: %v", err)
+	}
+	if time.Duration(args.MaxDuration) != 90*time.Second {
+		t.Errorf("Expected MaxDuration of 1m30s from milliseconds, got %s", args.MaxDuration)
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(configFile, []byte("max_duration: 1m30s\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Unsetenv("PLUGIN_MAX_DURATION")
+	t.Setenv("PLUGIN_CONFIG_FILE", configFile)
+	if args, err = LoadArgs(); err != nil {
+		t.Fatalf("LoadArgs() unexpected error: %v", err)
+	}
+	if time.Duration(args.MaxDuration) != 90*time.Second {
+		t.Errorf("Expected MaxDuration of 1m30s from the config file, got %s", args.MaxDuration)
+	}
+}
+
+func TestValidateThresholdsMaxDuration(t *testing.T) {
+	args := Args{ThresholdMode: ThresholdModeAbsolute, MaxDuration: Duration(90 * time.Second)}
+
+	if err := validateThresholds(Results{Total: 1, DurationMS: 90000}, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+	err := validateThresholds(Results{Total: 1, DurationMS: 90001}, args)
+	if err == nil || !strings.Contains(err.Error(), "exceeded MaxDuration (1m30s)") {
+		t.Errorf("validateThresholds() expected MaxDuration error, got %v", err)
+	}
+}
+
+func TestProcessFileWithInvalidDurations(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected float64
+	}{
+		// The negative duration counts as zero
+		{name: "NoCap", args: Args{}, expected: 10 + 604800000},
+		// The week-long duration is capped to a day
+		{name: "Capped", args: Args{MaxTestDuration: Duration(24 * time.Hour)}, expected: 10 + 86400000},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := processFile("../testdata/durations/testng-invalid-durations.xml", tc.args)
+			if err != nil {
+				t.Fatalf("processFile() unexpected error: %v", err)
+			}
+			if results.DurationMS != tc.expected {
+				t.Errorf("Expected a total duration of %.0f ms, got %.0f ms", tc.expected, results.DurationMS)
+			}
+		})
+	}
+
+	var warnings []string
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	expected := []string{
+		"Invalid DurationMS -5000 for test 'test2'; counting it as 0 ms",
+		"Invalid DurationMS -5000 for test 'test2'; counting it as 0 ms",
+		"Implausible DurationMS 604800000 for test 'test3'; capping it to MaxTestDuration (24h0m0s)",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Warnings mismatch:\nwant %q\ngot  %q", expected, warnings)
+	}
+}
+
+func TestSuiteDurationTolerance(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+
+	tests := []struct {
+		name      string
+		tolerance float64
+		expected  []string
+	}{
+		{name: "Disabled", tolerance: 0},
+		// The tests sum to 30 ms against a declared 400 ms, a 92.5% difference
+		{name: "WithinTolerance", tolerance: 95},
+		{
+			name:      "ExceedsTolerance",
+			tolerance: 50,
+			expected:  []string{"Suite 'DurationSuite' test durations sum to 30.00 ms but the suite declares 400.00 ms, a 92.50% difference exceeding SuiteDurationTolerance (50.00%)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			if _, err := processFile("../testdata/durations/testng-test-durations.xml", Args{SuiteDurationTolerance: tc.tolerance}); err != nil {
+				t.Fatalf("processFile() unexpected error: %v", err)
+			}
+
+			var warnings []string
+			for _, entry := range hook.Entries {
+				if entry.Level == logrus.WarnLevel {
+					warnings = append(warnings, entry.Message)
+				}
+			}
+			if strings.Join(warnings, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("Warnings mismatch:\nwant %q\ngot  %q", tc.expected, warnings)
+			}
+		})
+	}
+}
+
+func TestExecMaxInvalidDurationRatio(t *testing.T) {
+	// testng-corrupt-durations.xml has 4 invalid durations in 6 tests
+	tests := []struct {
+		name      string
+		maxRatio  float64
+		fail      bool
+		expectErr bool
+		expectLog bool
+	}{
+		{name: "Below", maxRatio: 0.7, fail: true},
+		{name: "AboveWarns", maxRatio: 0.5, expectLog: true},
+		{name: "AboveFails", maxRatio: 0.5, fail: true, expectErr: true, expectLog: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logrus.SetLevel(logrus.InfoLevel)
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			args := Args{
+				ReportFilenamePattern:      "../testdata/durations/testng-corrupt-durations.xml",
+				ThresholdMode:              ThresholdModeAbsolute,
+				MaxInvalidDurationRatio:    tc.maxRatio,
+				FailOnInvalidDurationRatio: tc.fail,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Exec() error = %v, expectErr %v", err, tc.expectErr)
+			}
+			if reason, _ := FailReasonOf(err); tc.expectErr && reason != FailReasonInvalidDurationRatio {
+				t.Errorf("Fail reason = %q, want %q", reason, FailReasonInvalidDurationRatio)
+			}
+
+			logged := false
+			for _, entry := range hook.Entries {
+				if strings.Contains(entry.Message, "4 of 6 tests (0.67) have an invalid duration") {
+					logged = true
+				}
+			}
+			if logged != tc.expectLog {
+				t.Errorf("Invalid duration ratio logged = %v, want %v", logged, tc.expectLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// parseExpectedCounts parses a manifest of the form "suite:count,..." into a
+// map of suite name to expected test count.
+func parseExpectedCounts(manifest string) (map[string]int, error) {
+	expected := make(map[string]int)
+	if strings.TrimSpace(manifest) == "" {
+		return expected, nil
+	}
+
+	for _, entry := range strings.Split(manifest, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// Split on the last colon so suite names may contain colons
+		idx := strings.LastIndex(entry, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid ExpectedCounts entry '%s'. Use the format suite:count", entry)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(entry[idx+1:]))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid count in ExpectedCounts entry '%s'. It must be a non-negative integer", entry)
+		}
+		expected[strings.TrimSpace(entry[:idx])] = count
+	}
+
+	return expected, nil
+}
+
+// validateExpectedCounts returns an error listing every suite whose actual
+// test count is below the expected count. Suites above their expected count
+// are only logged.
+func validateExpectedCounts(expected map[string]int, actual map[string]Results) error {
+	suites := make([]string, 0, len(expected))
+	for suite := range expected {
+		suites = append(suites, suite)
+	}
+	sort.Strings(suites)
+
+	var missing []string
+	for _, suite := range suites {
+		want, got := expected[suite], actual[suite].Total
+		switch {
+		case got < want:
+			missing = append(missing, fmt.Sprintf("%s (expected %d, found %d)", suite, want, got))
+		case got > want:
+			logrus.Infof("\nSuite '%s' has more tests than expected (expected %d, found %d)", suite, want, got)
+		}
+	}
+
+	if len(missing) > 0 {
+		return withFailReason(FailReasonExpectedCounts, fmt.Errorf("\nexpected test count validation failed for suites: %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
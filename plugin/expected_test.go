@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseExpectedCounts(t *testing.T) {
+	expected, err := parseExpectedCounts("Suite1:3, Suite:With:Colons:2")
+	if err != nil {
+		t.Fatalf("parseExpectedCounts() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]int{"Suite1": 3, "Suite:With:Colons": 2}, expected); diff != "" {
+		t.Errorf("parseExpectedCounts() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, manifest := range []string{"Suite1", "Suite1:many", "Suite1:-1", ":3"} {
+		if _, err := parseExpectedCounts(manifest); err == nil {
+			t.Errorf("parseExpectedCounts(%q) expected error", manifest)
+		}
+	}
+}
+
+func TestValidateExpectedCounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  map[string]int
+		actual    map[string]Results
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:     "Matching",
+			expected: map[string]int{"Suite1": 3, "Suite2": 3},
+			actual:   map[string]Results{"Suite1": {Total: 3}, "Suite2": {Total: 3}},
+		},
+		{
+			name:      "UnderCount",
+			expected:  map[string]int{"Suite1": 3, "Suite2": 5},
+			actual:    map[string]Results{"Suite1": {Total: 3}, "Suite2": {Total: 3}},
+			expectErr: true,
+			errMsg:    "Suite2 (expected 5, found 3)",
+		},
+		{
+			name:      "MissingSuite",
+			expected:  map[string]int{"Suite3": 1},
+			actual:    map[string]Results{"Suite1": {Total: 3}},
+			expectErr: true,
+			errMsg:    "Suite3 (expected 1, found 0)",
+		},
+		{
+			name:     "OverCount",
+			expected: map[string]int{"Suite1": 2},
+			actual:   map[string]Results{"Suite1": {Total: 3}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateExpectedCounts(tc.expected, tc.actual)
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Errorf("validateExpectedCounts() expected error %q but got %v", tc.errMsg, err)
+				}
+			} else if err != nil {
+				t.Errorf("validateExpectedCounts() unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FailReason is a stable code categorizing why the build was marked as failed.
+type FailReason string
+
+// Reason codes set in the TESTNG_FAIL_REASON output variable
+const (
+	FailReasonAbsoluteFailures     FailReason = "ABSOLUTE_FAILURES"
+	FailReasonPercentageFailures   FailReason = "PERCENTAGE_FAILURES"
+	FailReasonSkips                FailReason = "SKIPS"
+	FailReasonErrors               FailReason = "ERRORS"
+	FailReasonConfigSkips          FailReason = "CONFIG_SKIPS"
+	FailReasonAssertionFailures    FailReason = "ASSERTION_FAILURES"
+	FailReasonErrorFailures        FailReason = "ERROR_FAILURES"
+	FailReasonConfigFailure        FailReason = "CONFIG_FAILURE"
+	FailReasonZeroDuration         FailReason = "ZERO_DURATION"
+	FailReasonMissingException     FailReason = "MISSING_EXCEPTION"
+	FailReasonSkippedClasses       FailReason = "SKIPPED_CLASSES"
+	FailReasonEmptyClasses         FailReason = "EMPTY_CLASSES"
+	FailReasonUnknownStatus        FailReason = "UNKNOWN_STATUS"
+	FailReasonMaxDuration          FailReason = "MAX_DURATION"
+	FailReasonThresholdRule        FailReason = "THRESHOLD_RULE"
+	FailReasonPassRateDrop         FailReason = "PASS_RATE_DROP"
+	FailReasonExpectedCounts       FailReason = "EXPECTED_COUNTS"
+	FailReasonMinMSPerTest         FailReason = "MIN_MS_PER_TEST"
+	FailReasonUniformCounts        FailReason = "UNIFORM_COUNTS"
+	FailReasonMinClasses           FailReason = "MIN_CLASSES"
+	FailReasonMinAssertions        FailReason = "MIN_ASSERTIONS"
+	FailReasonMinHealthScore       FailReason = "MIN_HEALTH_SCORE"
+	FailReasonFlakyRate            FailReason = "FLAKY_RATE"
+	FailReasonFlakyToFailRatio     FailReason = "FLAKY_TO_FAIL_RATIO"
+	FailReasonInvalidDurationRatio FailReason = "INVALID_DURATION_RATIO"
+	FailReasonBaselinePassing      FailReason = "BASELINE_PASSING"
+	FailReasonWarnings             FailReason = "WARNINGS"
+)
+
+// FailReasonOutput is the output variable holding the fail reason code.
+const FailReasonOutput = "TESTNG_FAIL_REASON"
+
+// FailReasonError is a build failure carrying its reason code. Its message
+// is that of the wrapped error.
+type FailReasonError struct {
+	Reason FailReason
+	Err    error
+}
+
+func (e *FailReasonError) Error() string { return e.Err.Error() }
+func (e *FailReasonError) Unwrap() error { return e.Err }
+
+// withFailReason tags err with reason. It returns nil when err is nil.
+func withFailReason(reason FailReason, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FailReasonError{Reason: reason, Err: err}
+}
+
+// FailReasonOf returns the reason code carried by err, if any.
+func FailReasonOf(err error) (FailReason, bool) {
+	var reasonErr *FailReasonError
+	if errors.As(err, &reasonErr) {
+		return reasonErr.Reason, true
+	}
+	return "", false
+}
+
+// writeOutputVariable appends name=value to the file named by DRONE_OUTPUT,
+// which Drone and Harness expose as step output variables. It does nothing
+// when the variable is not set.
+func writeOutputVariable(name, value string) error {
+	filename := os.Getenv("DRONE_OUTPUT")
+	if filename == "" {
+		logrus.Debugf("DRONE_OUTPUT is not set; not writing output variable %s", name)
+		return nil
+	}
+
+	value = strings.ReplaceAll(value, "\n", " ")
+	if err := appendFile(filename, []byte(name+"="+value+"\n")); err != nil {
+		return fmt.Errorf("failed to write output variable %s to %s: %w", name, filename, err)
+	}
+	return nil
+}
+
+// recordFailReason writes the reason code carried by err, if any, to the
+// TESTNG_FAIL_REASON output variable.
+func recordFailReason(err error) {
+	reason, ok := FailReasonOf(err)
+	if !ok {
+		return
+	}
+	if err := writeOutputVariable(FailReasonOutput, string(reason)); err != nil {
+		logrus.Warn(err.Error())
+	}
+}
@@ -0,0 +1,142 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidateThresholdsFailReason(t *testing.T) {
+	results := Results{
+		Total: 10, Failures: 2, Skipped: 3, Errors: 3, DurationMS: 100,
+		ConfigSkips: 1, AssertionFailures: 2, ErrorFailures: 0,
+		FullySkippedClasses: 2, EmptyClasses: 1, MissingExceptions: 1,
+	}
+	absolute := Args{ThresholdMode: ThresholdModeAbsolute}
+	percentage := Args{ThresholdMode: ThresholdModePercentage}
+
+	with := func(base Args, set func(*Args)) Args {
+		set(&base)
+		return base
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected FailReason
+	}{
+		{name: "ConfigFailure", args: with(absolute, func(a *Args) { a.FailureOnFailedTestConfig = true }), expected: FailReasonConfigFailure},
+		{name: "MissingException", args: with(absolute, func(a *Args) { a.FailOnMissingException = true }), expected: FailReasonMissingException},
+		{name: "SkippedClasses", args: with(absolute, func(a *Args) { a.MaxFullySkippedClasses = 1 }), expected: FailReasonSkippedClasses},
+		{name: "EmptyClasses", args: with(absolute, func(a *Args) { a.FailOnEmptyClasses = true }), expected: FailReasonEmptyClasses},
+		{name: "MaxDuration", args: with(absolute, func(a *Args) { a.MaxDuration = Duration(50 * time.Millisecond) }), expected: FailReasonMaxDuration},
+		{name: "AbsoluteFailures", args: with(absolute, func(a *Args) { a.FailedFails = 1 }), expected: FailReasonAbsoluteFailures},
+		{name: "AbsoluteSkips", args: with(absolute, func(a *Args) { a.FailedSkips = 2 }), expected: FailReasonSkips},
+		{name: "AbsoluteErrors", args: with(absolute, func(a *Args) { a.FailedErrors = 1 }), expected: FailReasonErrors},
+		{name: "AssertionFailures", args: with(absolute, func(a *Args) { a.FailedAssertionFailures = 1 }), expected: FailReasonAssertionFailures},
+		{name: "WithinThresholds", args: with(absolute, func(a *Args) { a.FailedFails = 5 }), expected: ""},
+		{name: "PercentageFailures", args: with(percentage, func(a *Args) { a.FailedFails = 10 }), expected: FailReasonPercentageFailures},
+		{name: "PercentageSkips", args: with(percentage, func(a *Args) { a.FailedSkips = 20 }), expected: FailReasonSkips},
+		{name: "ThresholdRule", args: with(absolute, func(a *Args) { a.ThresholdRules = `[{"metric":"failures","mode":"absolute","limit":1}]` }), expected: FailReasonThresholdRule},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateThresholds(results, tc.args)
+			reason, ok := FailReasonOf(err)
+			if tc.expected == "" {
+				if err != nil {
+					t.Errorf("validateThresholds() unexpected error: %v", err)
+				}
+				return
+			}
+			if !ok || reason != tc.expected {
+				t.Errorf("FailReasonOf() = %q, %v, want %q (error: %v)", reason, ok, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestExecWritesFailReason(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "drone-output.env")
+	t.Setenv("DRONE_OUTPUT", outputFile)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModePercentage,
+		FailedFails:           10,
+	}
+	err := Exec(context.Background(), args)
+	if reason, _ := FailReasonOf(err); reason != FailReasonPercentageFailures {
+		t.Fatalf("Exec() expected a %s error, got %v", FailReasonPercentageFailures, err)
+	}
+
+	data, readErr := os.ReadFile(outputFile)
+	if readErr != nil {
+		t.Fatalf("Failed to read output file: %v", readErr)
+	}
+	if got := string(data); got != "TESTNG_FAIL_REASON=PERCENTAGE_FAILURES\n" {
+		t.Errorf("Output variables mismatch: %q", got)
+	}
+}
+
+func TestExecGateFailReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     Args
+		expected FailReason
+	}{
+		{name: "ExpectedCounts", args: Args{ExpectedCounts: "Suite1:10"}, expected: FailReasonExpectedCounts},
+		{name: "MinMSPerTest", args: Args{MinMSPerTest: 100, FailOnMinMSPerTest: true}, expected: FailReasonMinMSPerTest},
+		{name: "MinClasses", args: Args{MinClasses: 5}, expected: FailReasonMinClasses},
+		{name: "MinHealthScore", args: Args{MinHealthScore: 99}, expected: FailReasonMinHealthScore},
+		{name: "FailOnWarnings", args: Args{FailOnWarnings: true, MinMSPerTest: 100}, expected: FailReasonWarnings},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "drone-output.env")
+			t.Setenv("DRONE_OUTPUT", outputFile)
+
+			args := tc.args
+			args.ReportFilenamePattern = "../testdata/testng-report.xml"
+			args.ThresholdMode = ThresholdModeAbsolute
+			err := Exec(context.Background(), args)
+			if reason, _ := FailReasonOf(err); reason != tc.expected {
+				t.Fatalf("Exec() expected a %s error, got %v", tc.expected, err)
+			}
+
+			data, readErr := os.ReadFile(outputFile)
+			if readErr != nil {
+				t.Fatalf("Failed to read output file: %v", readErr)
+			}
+			if got, want := string(data), FailReasonOutput+"="+string(tc.expected)+"\n"; got != want {
+				t.Errorf("Output variables = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckFailReasons(t *testing.T) {
+	results := Results{Total: 10, Failures: 5, Flaky: 3, DurationMS: 100}
+	tests := []struct {
+		name     string
+		err      error
+		expected FailReason
+	}{
+		{name: "PassRateDrop", err: checkPassRateDrop(&RunRecord{Total: 10}, results, 10), expected: FailReasonPassRateDrop},
+		{name: "UniformCounts", err: checkUniformCounts(map[string]int{"a.xml": 1, "b.xml": 2}), expected: FailReasonUniformCounts},
+		{name: "MinAssertions", err: checkMinAssertions(Results{Total: 1, Assertions: 1, AssertionsReported: 1}, 5), expected: FailReasonMinAssertions},
+		{name: "FlakyRate", err: checkMaxFlakyRate(results, 10), expected: FailReasonFlakyRate},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if reason, ok := FailReasonOf(tc.err); !ok || reason != tc.expected {
+				t.Errorf("FailReasonOf() = %q, %v, want %q (error: %v)", reason, ok, tc.expected, tc.err)
+			}
+		})
+	}
+}
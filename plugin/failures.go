@@ -0,0 +1,40 @@
+package plugin
+
+import (
+	"strings"
+)
+
+// failureKind classifies a failed test by the exception it threw.
+type failureKind int
+
+const (
+	failureUnknown failureKind = iota
+	failureAssertion
+	failureError
+)
+
+// classifyFailure classifies a failed test as an assertion failure when its
+// exception class ends in AssertionError, e.g. java.lang.AssertionError, and
+// as an error for any other exception. The class is taken from the exception
+// element, falling back to the first line of the stack trace.
+func classifyFailure(test Test) failureKind {
+	class := exceptionClass(test)
+	switch {
+	case class == "":
+		return failureUnknown
+	case strings.HasSuffix(class, "AssertionError"):
+		return failureAssertion
+	default:
+		return failureError
+	}
+}
+
+// exceptionClass returns the exception class of test, if any.
+func exceptionClass(test Test) string {
+	if test.ExceptionClass != "" {
+		return test.ExceptionClass
+	}
+	// Stack traces start with the exception class, optionally followed by ": message"
+	class, _, _ := strings.Cut(firstLine(test.Exception), ":")
+	return strings.TrimSpace(class)
+}
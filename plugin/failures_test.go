@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		test     Test
+		expected failureKind
+	}{
+		{name: "AssertionErrorClass", test: Test{ExceptionClass: "java.lang.AssertionError"}, expected: failureAssertion},
+		{name: "CustomAssertionErrorClass", test: Test{ExceptionClass: "org.example.SoftAssertionError"}, expected: failureAssertion},
+		{name: "RuntimeExceptionClass", test: Test{ExceptionClass: "java.lang.IllegalStateException"}, expected: failureError},
+		{name: "ClassFromStacktrace", test: Test{Exception: "\n  java.lang.AssertionError: expected [1]\n  at Foo"}, expected: failureAssertion},
+		{name: "NoException", test: Test{}, expected: failureUnknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifyFailure(tc.test); got != tc.expected {
+				t.Errorf("classifyFailure() expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFailureKindsAndThresholds(t *testing.T) {
+	results, err := processFile("../testdata/failures/testng-failure-kinds.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+
+	expected := Results{Total: 3, Failures: 2, DurationMS: 15, AssertionFailures: 1, ErrorFailures: 1}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("processFile() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Assertion failures are tolerated while any error failure fails the build
+	args := Args{ThresholdMode: ThresholdModeAbsolute, FailedAssertionFailures: 5, FailedErrorFailures: 0}
+	if err := validateThresholds(results, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+
+	args.FailedAssertionFailures = 0
+	args.FailedErrorFailures = 1
+	if err := validateThresholds(results, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+
+	args.ThresholdMode = ThresholdModePercentage
+	args.FailedErrorFailures = 10
+	if err := validateThresholds(results, args); err == nil {
+		t.Errorf("validateThresholds() expected error for an error failure rate of 33%%")
+	}
+}
+
+func TestMissingException(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	results, err := processFile("../testdata/failures/testng-missing-exception.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.MissingExceptions != 1 {
+		t.Errorf("Expected 1 failure without an exception, got %d", results.MissingExceptions)
+	}
+
+	found := false
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel && entry.Message == "Test 'test1' in class 'com.test.MissingException' failed without an exception" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a warning about the failure without an exception")
+	}
+
+	tests := []struct {
+		name        string
+		failOn      bool
+		expectError bool
+	}{
+		{name: "WarnOnly", failOn: false, expectError: false},
+		{name: "FailOnMissingException", failOn: true, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern:  "../testdata/failures/testng-missing-exception.xml",
+				ThresholdMode:          ThresholdModeAbsolute,
+				FailOnMissingException: tc.failOn,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
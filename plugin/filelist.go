@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// readFileList reads report paths from a manifest file with one path per
+// line. Blank lines and lines starting with # are skipped.
+func readFileList(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file list %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file list %s: %w", filename, err)
+	}
+	return paths, nil
+}
+
+// appendUnique appends the paths not already in list, preserving order.
+func appendUnique(list, paths []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, path := range list {
+		seen[path] = true
+	}
+	for _, path := range paths {
+		if !seen[path] {
+			seen[path] = true
+			list = append(list, path)
+		}
+	}
+	return list
+}
+
+// newestPerDir keeps only the most recently modified file of each directory,
+// preserving the order of the kept files. Paths that cannot be inspected or
+// are not regular files are kept unchanged.
+func newestPerDir(paths []string) []string {
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	newest := make(map[string]candidate)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if current, ok := newest[dir]; !ok || info.ModTime().After(current.modTime) {
+			newest[dir] = candidate{path: path, modTime: info.ModTime()}
+		}
+	}
+
+	var kept []string
+	for _, path := range paths {
+		if current, ok := newest[filepath.Dir(path)]; ok && current.path != path {
+			logrus.Debugf("Skipping %s as %s is newer", path, current.path)
+			continue
+		}
+		kept = append(kept, path)
+	}
+	return kept
+}
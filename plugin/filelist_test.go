@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocateFilesWithFileList(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "reports.txt")
+	content := "# TestNG reports\n  ../testdata/testng-report.xml  \n\n../testdata/errors/testng-errors.xml\n"
+	if err := os.WriteFile(manifest, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	files, err := locateFiles("", Args{FileList: manifest})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	expected := []string{"../testdata/testng-report.xml", "../testdata/errors/testng-errors.xml"}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Listed files are added to the glob matches without duplicates
+	files, err = locateFiles("../testdata/testng-report*.xml", Args{FileList: manifest})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	expected = []string{"../testdata/testng-report-valid.xml", "../testdata/testng-report.xml", "../testdata/errors/testng-errors.xml"}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("locateFiles() with pattern mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadFileListMissing(t *testing.T) {
+	if _, err := readFileList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("readFileList() expected error for a missing manifest")
+	}
+}
+
+func TestLocateFilesNewestPerDir(t *testing.T) {
+	root := t.TempDir()
+	dirA := filepath.Join(root, "a")
+	dirB := filepath.Join(root, "b")
+	for _, dir := range []string{dirA, dirB} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	now := time.Now()
+	files := map[string]time.Time{
+		filepath.Join(dirA, "testng-1.xml"): now.Add(-2 * time.Hour),
+		filepath.Join(dirA, "testng-2.xml"): now.Add(-1 * time.Hour),
+		filepath.Join(dirB, "testng-1.xml"): now.Add(-3 * time.Hour),
+	}
+	for path, modTime := range files {
+		if err := os.WriteFile(path, []byte("<testng-results/>"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set modification time of %s: %v", path, err)
+		}
+	}
+
+	result, err := locateFiles(filepath.Join(root, "*", "*.xml"), Args{NewestPerDir: true})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	expected := []string{filepath.Join(dirA, "testng-2.xml"), filepath.Join(dirB, "testng-1.xml")}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flakyTests returns the names of the tests of a class that passed after one
+// or more attempts were retried, in order of first appearance. TestNG records
+// each discarded attempt of a retry analyzer as a test method marked retried.
+func flakyTests(tests []Test, failStatuses, skipStatuses map[string]bool) []string {
+	retried := make(map[string]bool)
+	passed := make(map[string]bool)
+	var order []string
+	for _, test := range tests {
+		if !retried[test.Name] && !passed[test.Name] {
+			order = append(order, test.Name)
+		}
+		status := strings.ToUpper(test.Status)
+		switch {
+		case test.Retried:
+			retried[test.Name] = true
+		case !failStatuses[status] && !skipStatuses[status]:
+			passed[test.Name] = true
+		}
+	}
+
+	var flaky []string
+	for _, name := range order {
+		if retried[name] && passed[name] {
+			flaky = append(flaky, name)
+		}
+	}
+	return flaky
+}
+
+// retryAnalyzers returns the retry analyzer recorded for each test name, taken
+// from the first attempt that records one. Tests without one are omitted.
+func retryAnalyzers(tests []Test) map[string]string {
+	analyzers := make(map[string]string)
+	for _, test := range tests {
+		if _, ok := analyzers[test.Name]; !ok && test.RetryAnalyzer != "" {
+			analyzers[test.Name] = test.RetryAnalyzer
+		}
+	}
+	return analyzers
+}
+
+// flakyRate returns the percentage of tests in results that were flaky.
+func flakyRate(results Results) float64 {
+	if results.Total == 0 {
+		return 0
+	}
+	return float64(results.Flaky) / float64(results.Total) * 100
+}
+
+// checkMaxFlakyRate returns an error when the percentage of flaky tests
+// exceeds maxRate. A flaky test passes in the end, so this catches a suite
+// that is degrading while the build is still green.
+func checkMaxFlakyRate(results Results, maxRate float64) error {
+	if maxRate <= 0 {
+		return nil
+	}
+
+	if rate := flakyRate(results); rate > maxRate {
+		return withFailReason(FailReasonFlakyRate, fmt.Errorf("\nbuild marked as failed as the flaky rate (%.2f%%, %d of %d tests) exceeds MaxFlakyRate (%.2f%%)", rate, results.Flaky, results.Total, maxRate))
+	}
+	return nil
+}
+
+// checkMaxFlakyToFailRatio returns an error when flaky tests outnumber genuine
+// failures by more than maxRatio, which points at failing infrastructure
+// rather than failing code. With no failures, any flaky test counts as an
+// infinite ratio unless allowWithoutFailures is set.
+func checkMaxFlakyToFailRatio(results Results, maxRatio float64, allowWithoutFailures bool) error {
+	if maxRatio <= 0 || results.Flaky == 0 {
+		return nil
+	}
+
+	if results.Failures == 0 {
+		if allowWithoutFailures {
+			return nil
+		}
+		return withFailReason(FailReasonFlakyToFailRatio, fmt.Errorf("\nbuild marked as failed as %d flaky tests and no failures give an infinite flaky-to-fail ratio, exceeding MaxFlakyToFailRatio (%.2f)", results.Flaky, maxRatio))
+	}
+
+	if ratio := float64(results.Flaky) / float64(results.Failures); ratio > maxRatio {
+		return withFailReason(FailReasonFlakyToFailRatio, fmt.Errorf("\nbuild marked as failed as the flaky-to-fail ratio (%.2f, %d flaky to %d failed tests) exceeds MaxFlakyToFailRatio (%.2f)", ratio, results.Flaky, results.Failures, maxRatio))
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestFlakyTests(t *testing.T) {
+	tests := []Test{
+		{Name: "pay", Status: "SKIP", Retried: true},
+		{Name: "pay", Status: "PASS"},
+		{Name: "ship", Status: "SKIP", Retried: true},
+		{Name: "ship", Status: "FAIL"},
+		{Name: "cart", Status: "PASS"},
+		{Name: "login", Status: "FAIL", Retried: true},
+		{Name: "login", Status: "PASS"},
+	}
+
+	failStatuses := statusSet("", DefaultFailStatuses)
+	skipStatuses := statusSet("", DefaultSkipStatuses)
+	got := flakyTests(tests, failStatuses, skipStatuses)
+	if diff := cmp.Diff([]string{"pay", "login"}, got); diff != "" {
+		t.Errorf("flakyTests() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecMaxFlakyRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRate     float64
+		expectError bool
+	}{
+		// testng-flaky.xml has 2 flaky tests out of 5, a rate of 40%
+		{name: "Below", maxRate: 50, expectError: false},
+		{name: "Above", maxRate: 30, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern: "../testdata/flaky/testng-flaky.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				MaxFlakyRate:          tc.maxRate,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestRetriedAttemptsNotCounted(t *testing.T) {
+	report, err := parseReport("../testdata/flaky/testng-flaky.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	// The 3 retried SKIP attempts add their duration but no tests or skips
+	results, failed, skipped := aggregateClassResults(report.Suites[0].Classes[0], Args{})
+	expected := Results{
+		Total:             5,
+		Failures:          1,
+		DurationMS:        80,
+		AssertionFailures: 1,
+		Flaky:             2,
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("Class results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"refund"}, failed); diff != "" {
+		t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("Expected no skipped tests, got %v", skipped)
+	}
+
+	// Tests that passed after a retry do not trip the skip threshold
+	args := Args{
+		ReportFilenamePattern: "../testdata/flaky/testng-flaky.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		FailedSkips:           1,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() unexpected error: %v", err)
+	}
+}
+
+func TestValidateInputsMaxFlakyRate(t *testing.T) {
+	args := Args{ReportFilenamePattern: "*.xml", MaxFlakyRate: 150}
+	if err := ValidateInputs(args); err == nil {
+		t.Errorf("Expected an error for a MaxFlakyRate above 100")
+	}
+}
+
+func TestExecMaxFlakyToFailRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRatio    float64
+		expectError bool
+	}{
+		// testng-flaky.xml has 2 flaky tests and 1 failure, a ratio of 2
+		{name: "Below", maxRatio: 3, expectError: false},
+		{name: "Equal", maxRatio: 2, expectError: false},
+		{name: "Above", maxRatio: 1.5, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern: "../testdata/flaky/testng-flaky.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				MaxFlakyToFailRatio:   tc.maxRatio,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestCheckMaxFlakyToFailRatioWithoutFailures(t *testing.T) {
+	results := Results{Total: 10, Flaky: 1}
+	err := checkMaxFlakyToFailRatio(results, 5, false)
+	if reason, _ := FailReasonOf(err); reason != FailReasonFlakyToFailRatio {
+		t.Errorf("Expected a %s error for flaky tests without failures, got %v", FailReasonFlakyToFailRatio, err)
+	}
+	if err := checkMaxFlakyToFailRatio(results, 5, true); err != nil {
+		t.Errorf("Unexpected error with AllowFlakyWithoutFailures: %v", err)
+	}
+	if err := checkMaxFlakyToFailRatio(Results{Total: 10}, 5, false); err != nil {
+		t.Errorf("Unexpected error without flaky tests: %v", err)
+	}
+}
+
+func TestFlakyRetryAnalyzers(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	report, err := parseReport("../testdata/flaky/testng-retry-analyzer.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	details := logTestNGReportDetails(report, Args{})
+	if details.Results.Flaky != 2 {
+		t.Errorf("Expected 2 flaky tests, got %+v", details.Results)
+	}
+
+	var flaky []string
+	for _, entry := range hook.Entries {
+		if strings.HasPrefix(entry.Message, "Flaky test ") {
+			flaky = append(flaky, entry.Message)
+		}
+	}
+	expected := []string{
+		"Flaky test 'create' in class 'com.test.Orders' passed after a retried attempt with retry analyzer 'com.test.retry.NetworkRetryAnalyzer'",
+		"Flaky test 'cancel' in class 'com.test.Orders' passed after a retried attempt with retry analyzer 'com.test.retry.DatabaseRetryAnalyzer'",
+	}
+	if diff := cmp.Diff(expected, flaky); diff != "" {
+		t.Errorf("Flaky log mismatch (-want +got):\n%s", diff)
+	}
+
+	// The analyzer is included in the detailed JSON and omitted where absent
+	summary := buildSummary(Results{}, []fileReport{{Report: report, Suites: details.Suites}}, Args{DetailedJSON: true})
+	analyzers := make(map[string]string)
+	for _, test := range summary.Suites[0].Tests {
+		analyzers[test.Name] = test.RetryAnalyzer
+	}
+	if analyzers["cancel"] != "com.test.retry.DatabaseRetryAnalyzer" || analyzers["list"] != "" {
+		t.Errorf("Unexpected retry analyzers: %v", analyzers)
+	}
+}
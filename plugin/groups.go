@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GroupOutcome holds the outcome counts of the methods belonging to a group.
+type GroupOutcome struct {
+	Name     string
+	Passed   int
+	Failures int
+	Skipped  int
+}
+
+// groupOutcomes correlates the methods of each suite group with the executed
+// tests by class and method name, and counts their outcomes.
+func groupOutcomes(suite Suite, args Args) []GroupOutcome {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	// Index test statuses by class and method name
+	statuses := make(map[string][]string)
+	for _, class := range suite.Classes {
+		for _, test := range class.Tests {
+			// Retried attempts were rerun; the last attempt has the outcome
+			if test.Retried {
+				continue
+			}
+			key := class.Name + "." + test.Name
+			statuses[key] = append(statuses[key], strings.ToUpper(test.Status))
+		}
+	}
+
+	outcomes := make([]GroupOutcome, 0, len(suite.Groups))
+	for _, group := range suite.Groups {
+		outcome := GroupOutcome{Name: group.Name}
+		for _, method := range group.Methods {
+			for _, status := range statuses[method.ClassName+"."+method.Name] {
+				switch {
+				case failStatuses[status]:
+					outcome.Failures++
+				case skipStatuses[status]:
+					outcome.Skipped++
+				default:
+					outcome.Passed++
+				}
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// logGroupOutcomes logs the pass/fail/skip breakdown of each group in a suite.
+func logGroupOutcomes(suite Suite, args Args) {
+	logrus.Infof("\nResults by Group:")
+	for _, outcome := range groupOutcomes(suite, args) {
+		logrus.Infof("\n- Group: %s | Passed: %d | Failures: %d | Skips: %d",
+			outcome.Name, outcome.Passed, outcome.Failures, outcome.Skipped)
+	}
+}
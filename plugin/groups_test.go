@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGroupOutcomes(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	// test1 (FAIL) belongs to group1; test2 (PASS) belongs to group1 and group2
+	expected := []GroupOutcome{
+		{Name: "group1", Passed: 1, Failures: 1},
+		{Name: "group2", Passed: 1},
+	}
+	if diff := cmp.Diff(expected, groupOutcomes(report.Suites[0], Args{})); diff != "" {
+		t.Errorf("groupOutcomes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLogGroupOutcomesWithMockLogger(t *testing.T) {
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	suite := Suite{
+		Name: "TestSuite",
+		Groups: []Group{
+			{Name: "fast", Methods: []Method{{Name: "Test1", ClassName: "Class1"}, {Name: "Test2", ClassName: "Class1"}}},
+			{Name: "slow", Methods: []Method{{Name: "Test2", ClassName: "Class1"}, {Name: "Test3", ClassName: "Class1"}}},
+		},
+		Classes: []Class{
+			{
+				Name: "Class1",
+				Tests: []Test{
+					{Name: "Test1", Status: "PASS"},
+					{Name: "Test2", Status: "FAIL"},
+					{Name: "Test3", Status: "SKIP"},
+				},
+			},
+		},
+	}
+
+	logGroupOutcomes(suite, Args{})
+
+	expectedEntries := []LogEntry{
+		{Message: "\nResults by Group:"},
+		{Message: "\n- Group: fast | Passed: 1 | Failures: 1 | Skips: 0"},
+		{Message: "\n- Group: slow | Passed: 0 | Failures: 1 | Skips: 1"},
+	}
+	for i, expected := range expectedEntries {
+		if i >= len(hook.Entries) {
+			t.Fatalf("Missing expected log entry: %+v", expected)
+		}
+		if actual := hook.Entries[i]; actual.Message != expected.Message {
+			t.Errorf("Log message mismatch at entry %d: expected %q, got %q", i, expected.Message, actual.Message)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+)
+
+// skipPenalty is the number of points deducted from the health score when
+// every test is skipped, scaled down linearly with the skip rate.
+const skipPenalty = 25
+
+// HealthScore condenses results into a single score between 0 and 100:
+//
+//	score = 100 × passed / executed − 25 × skipped / total
+//
+// where executed is total minus skipped. The first term is the pass rate of
+// the tests that actually ran and the second penalizes skipping, so a run
+// that skips most of its tests cannot score well. The score is clamped to
+// [0, 100] and is 0 when no tests ran. Results carry no history, so duration
+// trends are not part of the score; use MaxPassRateDrop to gate on trends.
+func HealthScore(results Results) float64 {
+	executed := results.Total - results.Skipped
+	if results.Total <= 0 || executed <= 0 {
+		return 0
+	}
+
+	passed := float64(executed - results.Failures)
+	score := 100*passed/float64(executed) - skipPenalty*float64(results.Skipped)/float64(results.Total)
+	return math.Max(0, math.Min(100, score))
+}
+
+// checkMinHealthScore returns an error when the health score of results is
+// below minScore.
+func checkMinHealthScore(results Results, minScore float64) error {
+	if minScore <= 0 {
+		return nil
+	}
+
+	if score := HealthScore(results); score < minScore {
+		return withFailReason(FailReasonMinHealthScore, fmt.Errorf("\nbuild marked as failed as the health score (%.2f) is below MinHealthScore (%.2f)", score, minScore))
+	}
+	return nil
+}
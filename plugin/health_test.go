@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestHealthScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  Results
+		expected float64
+	}{
+		{name: "AllPassed", results: Results{Total: 10}, expected: 100},
+		{name: "OneFailure", results: Results{Total: 10, Failures: 1}, expected: 90},
+		{name: "Skips", results: Results{Total: 10, Skipped: 2}, expected: 95},
+		{name: "FailuresAndSkips", results: Results{Total: 10, Failures: 1, Skipped: 2}, expected: 82.5},
+		{name: "AllFailed", results: Results{Total: 4, Failures: 4}, expected: 0},
+		{name: "AllSkipped", results: Results{Total: 4, Skipped: 4}, expected: 0},
+		{name: "NoTests", results: Results{}, expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := HealthScore(tc.results); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("HealthScore() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExecMinHealthScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		minScore    float64
+		expectError bool
+	}{
+		// testng-report.xml has 3 tests and 1 failure, a score of 66.67
+		{name: "Above", minScore: 60, expectError: false},
+		{name: "Below", minScore: 70, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				MinHealthScore:        tc.minScore,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateInputsMinHealthScore(t *testing.T) {
+	args := Args{ReportFilenamePattern: "*.xml", MinHealthScore: 101}
+	if err := ValidateInputs(args); err == nil {
+		t.Error("ValidateInputs() expected an error for a MinHealthScore above 100")
+	}
+}
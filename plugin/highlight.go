@@ -0,0 +1,35 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// highlightFirstFailure logs the first failed test of report at error level,
+// once per run, so it is not buried in the output of large failing runs.
+func (a *aggregate) highlightFirstFailure(report TestNGReport, args Args) {
+	class, test, ok := firstFailure(report, args)
+	if !ok {
+		return
+	}
+
+	a.firstFailure.Do(func() {
+		logrus.Errorf("\nFirst failure: %s | Class: %s | Exception: %s", test.Name, class.Name, firstLine(test.Exception))
+	})
+}
+
+// firstFailure returns the first test of report with a failing status.
+func firstFailure(report TestNGReport, args Args) (Class, Test, bool) {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	for _, suite := range report.Suites {
+		for _, class := range suite.Classes {
+			for _, test := range class.Tests {
+				if failStatuses[strings.ToUpper(test.Status)] {
+					return class, test, true
+				}
+			}
+		}
+	}
+	return Class{}, Test{}, false
+}
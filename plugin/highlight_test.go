@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestExecHighlightFirstFailure(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		HighlightFirstFailure: true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	highlight, summary := -1, -1
+	for i, entry := range hook.Entries {
+		if highlight < 0 && strings.Contains(entry.Message, "First failure: test1") {
+			highlight = i
+			if entry.Level != logrus.ErrorLevel {
+				t.Errorf("Expected first failure at error level, got %s", entry.Level)
+			}
+			if !strings.Contains(entry.Message, "Class: com.test.TestOne | Exception: java.lang.AssertionError") {
+				t.Errorf("Unexpected first failure message: %q", entry.Message)
+			}
+		}
+		if summary < 0 && strings.Contains(entry.Message, "Suite: Suite1") {
+			summary = i
+		}
+	}
+
+	if highlight < 0 || summary < 0 {
+		t.Fatalf("Expected both the first failure and the summary to be logged (highlight=%d, summary=%d)", highlight, summary)
+	}
+	if highlight > summary {
+		t.Errorf("Expected the first failure to be logged before the summary")
+	}
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// appendHistory appends record to filename as a single JSON line, creating
+// the file if it does not exist.
+func appendHistory(filename string, record RunRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+
+	if err := appendFile(filename, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append run record to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// lastRunRecord returns the last record of the history file, or nil when
+// the file does not exist or holds no records.
+func lastRunRecord(filename string) (*RunRecord, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file %s: %w", filename, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if last == "" {
+		return nil, nil
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal([]byte(last), &record); err != nil {
+		return nil, fmt.Errorf("failed to decode the last record of history file %s: %w", filename, err)
+	}
+	return &record, nil
+}
+
+// passRate returns the percentage of tests that neither failed nor were skipped.
+func passRate(total, failures, skipped int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(total-failures-skipped) / float64(total) * 100
+}
+
+// checkPassRateDrop returns an error when the pass rate of results dropped by
+// more than maxDrop percentage points compared to the previous run. Without
+// a previous run there is nothing to compare against.
+func checkPassRateDrop(previous *RunRecord, results Results, maxDrop float64) error {
+	if previous == nil || maxDrop <= 0 || previous.Total == 0 || results.Total == 0 {
+		return nil
+	}
+
+	before := passRate(previous.Total, previous.Failures, previous.Skipped)
+	current := passRate(results.Total, results.Failures, results.Skipped)
+	if drop := before - current; drop > maxDrop {
+		return withFailReason(FailReasonPassRateDrop, fmt.Errorf("\nbuild marked as failed as the pass rate dropped by %.2f percentage points (%.2f%% to %.2f%%), exceeding MaxPassRateDrop (%.2f)", drop, before, current, maxDrop))
+	}
+	return nil
+}
+
+// readHistory returns every record of the history file, oldest first, or
+// nil when the file does not exist.
+func readHistory(filename string) ([]RunRecord, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file %s: %w", filename, err)
+	}
+
+	var history []RunRecord
+	for i, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		var record RunRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode line %d of history file %s: %w", i+1, filename, err)
+		}
+		history = append(history, record)
+	}
+	return history, nil
+}
+
+// WeightedTrend returns the exponentially-weighted failure rate of history,
+// as a percentage. Each run is weighted by 0.5^(age/halfLife), where age is
+// the time between the run and the newest run, so a run one half-life older
+// counts half as much. This smooths out the noise of a single bad run while
+// still following a sustained change. Runs without tests are ignored and a
+// zero halfLife weights every run equally. It returns 0 when no run
+// has tests.
+func WeightedTrend(history []RunRecord, halfLife time.Duration) float64 {
+	var newest time.Time
+	for _, record := range history {
+		if record.Timestamp.After(newest) {
+			newest = record.Timestamp
+		}
+	}
+
+	var weighted, weights float64
+	for _, record := range history {
+		if record.Total <= 0 {
+			continue
+		}
+		weight := 1.0
+		if halfLife > 0 {
+			age := newest.Sub(record.Timestamp)
+			weight = math.Pow(0.5, float64(age)/float64(halfLife))
+		}
+		weighted += weight * float64(record.Failures) / float64(record.Total) * 100
+		weights += weight
+	}
+
+	if weights == 0 {
+		return 0
+	}
+	return weighted / weights
+}
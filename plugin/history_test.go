@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecHistoryFile(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		HistoryFile:           historyFile,
+		Label:                 "unit",
+	}
+
+	// Each run appends one record
+	for i := 0; i < 2; i++ {
+		if err := Exec(context.Background(), args); err != nil {
+			t.Fatalf("Exec() unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 history lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var record RunRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode history line %q: %v", line, err)
+		}
+		if record.Label != "unit" || record.Total != 3 || record.Failures != 1 || record.Timestamp.IsZero() {
+			t.Errorf("Unexpected history record: %+v", record)
+		}
+	}
+}
+
+func TestExecMaxPassRateDrop(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  string
+		expectErr bool
+	}{
+		{
+			name: "FirstRun",
+		},
+		{
+			// 3 tests with 1 failure is a pass rate of 66.67%
+			name:     "SmallDrop",
+			previous: `{"timestamp":"2024-05-28T12:14:37Z","total":4,"failures":1,"skipped":0,"duration_ms":10}`,
+		},
+		{
+			name:      "QualifyingDrop",
+			previous:  `{"timestamp":"2024-05-28T12:14:37Z","total":10,"failures":0,"skipped":0,"duration_ms":10}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+			if tc.previous != "" {
+				if err := os.WriteFile(historyFile, []byte(tc.previous+"\n"), 0644); err != nil {
+					t.Fatalf("Failed to write history file: %v", err)
+				}
+			}
+
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				HistoryFile:           historyFile,
+				MaxPassRateDrop:       10,
+			}
+			err := Exec(context.Background(), args)
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "pass rate dropped by 33.33 percentage points") {
+					t.Errorf("Exec() expected a pass rate drop error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Exec() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWeightedTrend(t *testing.T) {
+	now := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	history := []RunRecord{
+		// 50% failures two half-lives ago, weight 0.25
+		{Timestamp: now.Add(-48 * time.Hour), Total: 10, Failures: 5},
+		// No tests, ignored
+		{Timestamp: now.Add(-36 * time.Hour)},
+		// 20% failures one half-life ago, weight 0.5
+		{Timestamp: now.Add(-24 * time.Hour), Total: 10, Failures: 2},
+		// 0% failures now, weight 1
+		{Timestamp: now, Total: 10},
+	}
+
+	tests := []struct {
+		name     string
+		history  []RunRecord
+		halfLife time.Duration
+		expected float64
+	}{
+		{name: "Weighted", history: history, halfLife: 24 * time.Hour, expected: (0.25*50 + 0.5*20) / 1.75},
+		{name: "EqualWeights", history: history, halfLife: 0, expected: (50.0 + 20) / 3},
+		{name: "SingleRun", history: history[3:], halfLife: 24 * time.Hour, expected: 0},
+		{name: "Empty", history: nil, halfLife: 24 * time.Hour, expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := WeightedTrend(tc.history, tc.halfLife); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("WeightedTrend() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite represents a single suite in a JUnit XML report.
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase represents a single test case in a JUnit XML report.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure holds the failure details of a test case.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// buildJUnitXML converts the processed reports into a JUnit XML report. With
+// JUnitFailuresOnly, only failed test cases are included and the tests,
+// skipped and time attributes describe the included cases only, so tests
+// equals failures.
+func buildJUnitXML(reports []fileReport, args Args) JUnitTestSuites {
+	var root JUnitTestSuites
+	var totalMS float64
+
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			errorCount, _ := parseErrorCount(suite.Errors)
+			js := JUnitTestSuite{Name: suite.Name, Errors: errorCount}
+			var suiteMS float64
+
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					status := strings.ToUpper(test.Status)
+					failed := failStatuses[status]
+					if args.JUnitFailuresOnly && !failed {
+						continue
+					}
+
+					duration, _ := strconv.ParseFloat(test.DurationMS, 64)
+					suiteMS += duration
+
+					tc := JUnitTestCase{Name: test.Name, ClassName: class.Name, Time: formatSeconds(duration)}
+					switch {
+					case failed:
+						js.Failures++
+						tc.Failure = &JUnitFailure{
+							Message: firstLine(test.Exception),
+							Type:    exceptionClass(test),
+							Body:    strings.TrimSpace(test.Exception),
+						}
+					case skipStatuses[status]:
+						js.Skipped++
+						tc.Skipped = &struct{}{}
+					}
+					js.Tests++
+					js.TestCases = append(js.TestCases, tc)
+				}
+			}
+
+			js.Time = formatSeconds(suiteMS)
+			totalMS += suiteMS
+			root.Tests += js.Tests
+			root.Failures += js.Failures
+			root.Errors += js.Errors
+			root.Skipped += js.Skipped
+			root.Suites = append(root.Suites, js)
+		}
+	}
+
+	root.Time = formatSeconds(totalMS)
+	return root
+}
+
+// writeJUnitXML writes the processed reports to filename as a JUnit XML report.
+func writeJUnitXML(filename string, reports []fileReport, args Args) error {
+	data, err := xml.MarshalIndent(buildJUnitXML(reports, args), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit XML: %w", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit XML to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// junitSuiteDocument is a JUnit XML report with a single <testsuite> root.
+type junitSuiteDocument struct {
+	XMLName xml.Name `xml:"testsuite"`
+	JUnitTestSuite
+}
+
+// unsafeFilenameChars matches the characters replaced in suite file names.
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// suiteFilename converts a suite name into a file name safe on every
+// filesystem. Names already in use get a numeric suffix.
+func suiteFilename(name string, used map[string]bool) string {
+	base := strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "._")
+	if base == "" {
+		base = "suite"
+	}
+
+	filename := base + ".junit.xml"
+	for i := 2; used[filename]; i++ {
+		filename = fmt.Sprintf("%s-%d.junit.xml", base, i)
+	}
+	used[filename] = true
+	return filename
+}
+
+// writeJUnitXMLBySuite writes each suite of the processed reports to its own
+// <suitename>.junit.xml file in dir and returns the written paths.
+func writeJUnitXMLBySuite(dir string, reports []fileReport, args Args) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+
+	used := make(map[string]bool)
+	var paths []string
+	for _, suite := range buildJUnitXML(reports, args).Suites {
+		data, err := xml.MarshalIndent(junitSuiteDocument{JUnitTestSuite: suite}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JUnit XML for suite %s: %w", suite.Name, err)
+		}
+
+		path := filepath.Join(dir, suiteFilename(suite.Name, used))
+		data = append([]byte(xml.Header), data...)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write JUnit XML to %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
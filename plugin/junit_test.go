@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnitXML(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	reports := []fileReport{{Path: "testng-report.xml", Report: report}}
+
+	tests := []struct {
+		name      string
+		args      Args
+		tests     int
+		failures  int
+		testCases []string
+	}{
+		{
+			name:      "AllTestCases",
+			args:      Args{},
+			tests:     3,
+			failures:  1,
+			testCases: []string{"test1", "test2", "setUp"},
+		},
+		{
+			name:      "FailuresOnly",
+			args:      Args{JUnitFailuresOnly: true},
+			tests:     1,
+			failures:  1,
+			testCases: []string{"test1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "junit.xml")
+			if err := writeJUnitXML(output, reports, tc.args); err != nil {
+				t.Fatalf("writeJUnitXML() unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("Failed to read JUnit XML: %v", err)
+			}
+
+			var got JUnitTestSuites
+			if err := xml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Failed to decode JUnit XML: %v", err)
+			}
+			if got.Tests != tc.tests || got.Failures != tc.failures || len(got.Suites) != 1 {
+				t.Fatalf("testsuites mismatch: tests=%d failures=%d suites=%d", got.Tests, got.Failures, len(got.Suites))
+			}
+
+			suite := got.Suites[0]
+			if suite.Tests != tc.tests || suite.Failures != tc.failures {
+				t.Errorf("testsuite counts mismatch: tests=%d failures=%d", suite.Tests, suite.Failures)
+			}
+
+			var names []string
+			for _, testCase := range suite.TestCases {
+				names = append(names, testCase.Name)
+			}
+			if diff := cmp.Diff(tc.testCases, names); diff != "" {
+				t.Errorf("testcase names mismatch (-want +got):\n%s", diff)
+			}
+
+			failure := suite.TestCases[0].Failure
+			if failure == nil || failure.Type != "java.lang.AssertionError" {
+				t.Errorf("Expected an AssertionError failure on test1, got %+v", failure)
+			}
+		})
+	}
+}
+
+func TestWriteJUnitXMLBySuite(t *testing.T) {
+	var reports []fileReport
+	for _, path := range []string{"../testdata/testng-report.xml", "../testdata/errors/testng-errors.xml", "../testdata/testng-report.xml"} {
+		report, err := parseReport(path, Args{})
+		if err != nil {
+			t.Fatalf("parseReport() unexpected error: %v", err)
+		}
+		reports = append(reports, fileReport{Path: path, Report: report})
+	}
+	reports = append(reports, fileReport{Report: TestNGReport{Suites: []Suite{{
+		Name:    "Smoke / API: v2",
+		Classes: []Class{{Name: "com.test.Smoke", Tests: []Test{{Name: "ping", Status: "PASS", DurationMS: "1"}}}},
+	}}}})
+
+	dir := filepath.Join(t.TempDir(), "junit")
+	paths, err := writeJUnitXMLBySuite(dir, reports, Args{})
+	if err != nil {
+		t.Fatalf("writeJUnitXMLBySuite() unexpected error: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, "Suite1.junit.xml"),
+		filepath.Join(dir, "ErrorSuite.junit.xml"),
+		filepath.Join(dir, "Suite1-2.junit.xml"),
+		filepath.Join(dir, "Smoke_API_v2.junit.xml"),
+	}
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Errorf("Written files mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Suite1.junit.xml"))
+	if err != nil {
+		t.Fatalf("Failed to read per-suite JUnit XML: %v", err)
+	}
+	var got struct {
+		XMLName   xml.Name `xml:"testsuite"`
+		Name      string   `xml:"name,attr"`
+		Tests     int      `xml:"tests,attr"`
+		Failures  int      `xml:"failures,attr"`
+		TestCases []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"testcase"`
+	}
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Failed to decode per-suite JUnit XML: %v", err)
+	}
+	if got.Name != "Suite1" || got.Tests != 3 || got.Failures != 1 || len(got.TestCases) != 3 {
+		t.Errorf("Per-suite JUnit XML mismatch: %+v", got)
+	}
+}
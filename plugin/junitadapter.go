@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// junitInputSuite is a <testsuite> of a JUnit XML report being read. Some
+// producers nest suites, so suites may contain suites.
+type junitInputSuite struct {
+	Name      string            `xml:"name,attr"`
+	Errors    int               `xml:"errors,attr"`
+	Time      string            `xml:"time,attr"`
+	Suites    []junitInputSuite `xml:"testsuite"`
+	TestCases []junitInputCase  `xml:"testcase"`
+}
+
+// junitInputCase is a <testcase> of a JUnit XML report being read.
+type junitInputCase struct {
+	Name      string             `xml:"name,attr"`
+	ClassName string             `xml:"classname,attr"`
+	Time      string             `xml:"time,attr"`
+	Failure   *junitInputFailure `xml:"failure"`
+	Error     *junitInputFailure `xml:"error"`
+	Skipped   *struct{}          `xml:"skipped"`
+}
+
+// junitInputFailure is the <failure> or <error> of a JUnit test case.
+type junitInputFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// isJUnitRoot reports whether name is the root element of a JUnit XML report.
+func isJUnitRoot(name string) bool {
+	return name == "testsuites" || name == "testsuite"
+}
+
+// decodeJUnit decodes the JUnit report starting at start and converts it into
+// report, so JUnit results aggregate like TestNG results. Test cases with a
+// failure or error get the first of the configured FailStatuses and skipped
+// test cases the first of the configured SkipStatuses.
+func decodeJUnit(decoder *xml.Decoder, start xml.StartElement, args Args, report *TestNGReport) error {
+	var suites []junitInputSuite
+	if start.Name.Local == "testsuites" {
+		var root struct {
+			Suites []junitInputSuite `xml:"testsuite"`
+		}
+		if err := decoder.DecodeElement(&root, &start); err != nil {
+			return err
+		}
+		suites = root.Suites
+	} else {
+		var suite junitInputSuite
+		if err := decoder.DecodeElement(&suite, &start); err != nil {
+			return err
+		}
+		suites = []junitInputSuite{suite}
+	}
+
+	statuses := junitStatuses{
+		fail: firstStatus(args.FailStatuses, DefaultFailStatuses),
+		skip: firstStatus(args.SkipStatuses, DefaultSkipStatuses),
+	}
+	*report = TestNGReport{XMLName: start.Name}
+	for _, suite := range suites {
+		report.Suites = appendJUnitSuite(report.Suites, suite, statuses)
+	}
+	return nil
+}
+
+// junitStatuses are the TestNG statuses given to failed and skipped JUnit
+// test cases.
+type junitStatuses struct {
+	fail string
+	skip string
+}
+
+// firstStatus returns the first status of a comma-separated list of statuses,
+// using defaults when the list is empty.
+func firstStatus(statuses, defaults string) string {
+	if strings.TrimSpace(statuses) == "" {
+		statuses = defaults
+	}
+	for _, status := range strings.Split(statuses, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			return strings.ToUpper(status)
+		}
+	}
+	return ""
+}
+
+// appendJUnitSuite converts suite and its nested suites and appends them to
+// suites. Test cases are grouped into classes by class name. The suite's
+// errors attribute is not copied: errored test cases already count as
+// failures.
+func appendJUnitSuite(suites []Suite, suite junitInputSuite, statuses junitStatuses) []Suite {
+	if len(suite.TestCases) > 0 || len(suite.Suites) == 0 {
+		converted := Suite{
+			Name:     suite.Name,
+			Duration: junitMilliseconds(suite.Time),
+		}
+
+		classIndex := make(map[string]int)
+		for _, tc := range suite.TestCases {
+			className := tc.ClassName
+			if className == "" {
+				className = suite.Name
+			}
+			idx, ok := classIndex[className]
+			if !ok {
+				idx = len(converted.Classes)
+				classIndex[className] = idx
+				converted.Classes = append(converted.Classes, Class{Name: className})
+			}
+			converted.Classes[idx].Tests = append(converted.Classes[idx].Tests, junitTest(tc, statuses))
+		}
+		suites = append(suites, converted)
+	}
+
+	for _, nested := range suite.Suites {
+		suites = appendJUnitSuite(suites, nested, statuses)
+	}
+	return suites
+}
+
+// junitTest converts a JUnit test case into a TestNG test method.
+func junitTest(tc junitInputCase, statuses junitStatuses) Test {
+	test := Test{Name: tc.Name, Status: "PASS", DurationMS: junitMilliseconds(tc.Time)}
+
+	failure := tc.Failure
+	if failure == nil {
+		failure = tc.Error
+	}
+	switch {
+	case failure != nil:
+		test.Status = statuses.fail
+		test.ExceptionClass = failure.Type
+		test.Exception = strings.TrimSpace(failure.Body)
+		if test.Exception == "" {
+			test.Exception = failure.Message
+		}
+	case tc.Skipped != nil:
+		test.Status = statuses.skip
+	}
+	return test
+}
+
+// junitMilliseconds converts a JUnit time in seconds to milliseconds. Values
+// that are not numbers are returned unchanged so they are reported as invalid.
+func junitMilliseconds(seconds string) string {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(seconds, ",", ""), 64)
+	if err != nil {
+		return seconds
+	}
+	return strconv.FormatFloat(value*1000, 'f', -1, 64)
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAggregateJUnitAlongsideTestNG(t *testing.T) {
+	files := []string{"../testdata/testng-report.xml", "../testdata/junit/junit-report.xml"}
+
+	agg := processFiles(files, Args{AllowJUnit: true})
+	if len(agg.SkippedFiles) > 0 {
+		t.Fatalf("Unexpected skipped files: %v", agg.SkippedFiles)
+	}
+
+	expected := Results{
+		Total:             7,
+		Failures:          3,
+		Skipped:           1,
+		DurationMS:        50,
+		AssertionFailures: 2,
+		ErrorFailures:     1,
+	}
+	if diff := cmp.Diff(expected, agg.Results); diff != "" {
+		t.Errorf("Aggregated results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"test1", "testUpdate", "testConnect"}, agg.FailedTests); diff != "" {
+		t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without AllowJUnit the JUnit report is not recognized
+	agg = processFiles(files, Args{})
+	if diff := cmp.Diff([]string{"../testdata/junit/junit-report.xml"}, agg.SkippedFiles); diff != "" {
+		t.Errorf("Skipped files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseJUnitReport(t *testing.T) {
+	report, err := parseReport("../testdata/junit/junit-report.xml", Args{AllowJUnit: true})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	if len(report.Suites) != 1 || report.Suites[0].Name != "JUnitSuite" {
+		t.Fatalf("Expected the JUnitSuite suite, got %+v", report.Suites)
+	}
+
+	got := make(map[string][]string)
+	for _, class := range report.Suites[0].Classes {
+		for _, test := range class.Tests {
+			got[class.Name] = append(got[class.Name], test.Name+":"+test.Status+":"+test.DurationMS)
+		}
+	}
+	expected := map[string][]string{
+		"com.test.Orders":   {"testCreate:PASS:10", "testUpdate:FAIL:15", "testDelete:SKIP:5"},
+		"com.test.Database": {"testConnect:FAIL:5"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Converted tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseJUnitReportCustomStatuses(t *testing.T) {
+	args := Args{AllowJUnit: true, FailStatuses: "BROKEN, FAIL", SkipStatuses: "IGNORED"}
+	report, err := parseReport("../testdata/junit/junit-report.xml", args)
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, class := range report.Suites[0].Classes {
+		for _, test := range class.Tests {
+			got = append(got, test.Name+":"+test.Status)
+		}
+	}
+	expected := []string{"testCreate:PASS", "testUpdate:BROKEN", "testDelete:IGNORED", "testConnect:BROKEN"}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Converted statuses mismatch (-want +got):\n%s", diff)
+	}
+
+	// The converted tests count as failed and skipped under the same statuses
+	agg := processFiles([]string{"../testdata/junit/junit-report.xml"}, args)
+	expectedResults := Results{
+		Total:             4,
+		Failures:          2,
+		Skipped:           1,
+		DurationMS:        35,
+		AssertionFailures: 1,
+		ErrorFailures:     1,
+	}
+	if diff := cmp.Diff(expectedResults, agg.Results); diff != "" {
+		t.Errorf("Aggregated results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseJUnitReportWithValidateSchema(t *testing.T) {
+	report, err := parseReport("../testdata/junit/junit-report.xml", Args{AllowJUnit: true, ValidateSchema: true})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	if len(report.Suites) != 1 || report.Suites[0].Name != "JUnitSuite" {
+		t.Errorf("Expected the JUnitSuite suite, got %+v", report.Suites)
+	}
+
+	// Without AllowJUnit the JUnit root still fails the check
+	if _, err := parseReport("../testdata/junit/junit-report.xml", Args{ValidateSchema: true}); err == nil {
+		t.Error("parseReport() expected an error for a JUnit root without AllowJUnit")
+	}
+}
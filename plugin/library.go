@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Outcome is the result category of a test status.
+type Outcome int
+
+// Test outcomes. Ignored tests are left out of the aggregated results.
+const (
+	OutcomePass Outcome = iota
+	OutcomeFail
+	OutcomeSkip
+	OutcomeIgnore
+)
+
+// AggregateOption configures Aggregate.
+type AggregateOption func(*aggregateOptions)
+
+type aggregateOptions struct {
+	classify func(status string) Outcome
+}
+
+// WithStatusClassifier sets the function used to map test statuses to
+// outcomes, e.g. to support statuses produced by custom TestNG listeners.
+func WithStatusClassifier(classify func(status string) Outcome) AggregateOption {
+	return func(o *aggregateOptions) {
+		o.classify = classify
+	}
+}
+
+// DefaultStatusClassifier maps statuses using the plugin's default
+// DefaultFailStatuses and DefaultSkipStatuses. Anything else passes; unlike
+// the plugin, unrecognized statuses are not counted separately.
+func DefaultStatusClassifier(status string) Outcome {
+	status = strings.ToUpper(status)
+	switch {
+	case statusSet("", DefaultFailStatuses)[status]:
+		return OutcomeFail
+	case statusSet("", DefaultSkipStatuses)[status]:
+		return OutcomeSkip
+	default:
+		return OutcomePass
+	}
+}
+
+// ParseReport parses a TestNG XML report file.
+func ParseReport(filename string) (TestNGReport, error) {
+	return parseReport(filename, Args{})
+}
+
+// Aggregate sums the results of every test in reports. Tests with an
+// invalid or missing duration are counted but add nothing to the duration.
+func Aggregate(reports []TestNGReport, opts ...AggregateOption) Results {
+	options := aggregateOptions{classify: DefaultStatusClassifier}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var results Results
+	for _, report := range reports {
+		for _, suite := range report.Suites {
+			results.Errors += suiteErrors(suite)
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					switch options.classify(test.Status) {
+					case OutcomeIgnore:
+						continue
+					case OutcomeFail:
+						results.Failures++
+						switch classifyFailure(test) {
+						case failureAssertion:
+							results.AssertionFailures++
+						case failureError:
+							results.ErrorFailures++
+						}
+					case OutcomeSkip:
+						results.Skipped++
+						if test.IsConfig {
+							results.ConfigSkips++
+						}
+					}
+					results.Total++
+
+					if duration, err := strconv.ParseFloat(test.DurationMS, 64); err == nil {
+						results.DurationMS += duration
+					}
+				}
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAggregateDefaultClassifier(t *testing.T) {
+	report, err := ParseReport("../testdata/testng-report.xml")
+	if err != nil {
+		t.Fatalf("ParseReport() unexpected error: %v", err)
+	}
+
+	expected := Results{Total: 3, Failures: 1, DurationMS: 15, AssertionFailures: 1}
+	if diff := cmp.Diff(expected, Aggregate([]TestNGReport{report})); diff != "" {
+		t.Errorf("Aggregate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDefaultStatusClassifierMatchesDefaultStatuses(t *testing.T) {
+	for status := range statusSet("", DefaultFailStatuses) {
+		if got := DefaultStatusClassifier(strings.ToLower(status)); got != OutcomeFail {
+			t.Errorf("DefaultStatusClassifier(%q) = %v, want OutcomeFail", status, got)
+		}
+	}
+	for status := range statusSet("", DefaultSkipStatuses) {
+		if got := DefaultStatusClassifier(strings.ToLower(status)); got != OutcomeSkip {
+			t.Errorf("DefaultStatusClassifier(%q) = %v, want OutcomeSkip", status, got)
+		}
+	}
+	for _, status := range []string{PassStatus, "BROKEN"} {
+		if got := DefaultStatusClassifier(status); got != OutcomePass {
+			t.Errorf("DefaultStatusClassifier(%q) = %v, want OutcomePass", status, got)
+		}
+	}
+}
+
+func TestAggregateCustomClassifier(t *testing.T) {
+	report := TestNGReport{Suites: []Suite{{Classes: []Class{{
+		Name: "Class1",
+		Tests: []Test{
+			{Name: "test1", Status: "PASS", DurationMS: "10"},
+			{Name: "test2", Status: "BROKEN", DurationMS: "20"},
+			{Name: "test3", Status: "QUARANTINED", DurationMS: "30"},
+			{Name: "test4", Status: "SKIP", DurationMS: "40"},
+		},
+	}}}}}
+
+	classify := func(status string) Outcome {
+		switch status {
+		case "BROKEN":
+			return OutcomeFail
+		case "QUARANTINED":
+			return OutcomeIgnore
+		default:
+			return DefaultStatusClassifier(status)
+		}
+	}
+
+	expected := Results{Total: 3, Failures: 1, Skipped: 1, DurationMS: 70}
+	if diff := cmp.Diff(expected, Aggregate([]TestNGReport{report}, WithStatusClassifier(classify))); diff != "" {
+		t.Errorf("Aggregate() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLocateFilesLimitFiles(t *testing.T) {
+	data, err := os.ReadFile("../testdata/testng-report.xml")
+	if err != nil {
+		t.Fatalf("Failed to read sample report: %v", err)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"c.xml", "a.xml", "b.xml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatalf("Failed to write report: %v", err)
+		}
+	}
+
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{LimitFiles: 2}
+	files, err := locateFiles(filepath.Join(dir, "*.xml"), args)
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	expected := []string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "b.xml")}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	warned := false
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "results are partial") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("Expected a warning that results are partial")
+	}
+
+	// Only the limited files are processed: each sample report has 3 tests
+	if total := processFiles(files, args).Results.Total; total != 6 {
+		t.Errorf("Expected 6 tests from 2 files, got %d", total)
+	}
+
+	args.LimitFiles = 0
+	if files, _ := locateFiles(filepath.Join(dir, "*.xml"), args); len(files) != 3 {
+		t.Errorf("Expected all 3 files without a limit, got %v", files)
+	}
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// parseLogFields parses a comma-separated list of key=value pairs, e.g.
+// "build=42,commit=abc123". Values may contain '=' but not ','.
+func parseLogFields(spec string) (logrus.Fields, error) {
+	fields := make(logrus.Fields)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid log field '%s': expected key=value", pair)
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	return fields, nil
+}
+
+// fieldInjector is a logrus hook that attaches a fixed set of fields to
+// every entry logged while it is installed.
+type fieldInjector struct {
+	fields logrus.Fields
+	hooks  logrus.LevelHooks
+}
+
+// installFieldInjector adds a field injector to the standard logger ahead of
+// the installed hooks, so they see the injected fields. Call remove to
+// restore the previously installed hooks.
+func installFieldInjector(fields logrus.Fields) *fieldInjector {
+	logger := logrus.StandardLogger()
+
+	injector := &fieldInjector{fields: fields, hooks: make(logrus.LevelHooks)}
+	installed := make(logrus.LevelHooks)
+	for _, level := range logrus.AllLevels {
+		injector.hooks[level] = append([]logrus.Hook(nil), logger.Hooks[level]...)
+		installed[level] = append([]logrus.Hook{injector}, logger.Hooks[level]...)
+	}
+	logger.ReplaceHooks(installed)
+	return injector
+}
+
+// Levels returns the log levels the fields are attached to.
+func (f *fieldInjector) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire attaches the fields to entry. Fields set on the entry itself win.
+func (f *fieldInjector) Fire(entry *logrus.Entry) error {
+	for key, value := range f.fields {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
+// remove uninstalls the injector by restoring the previous hooks.
+func (f *fieldInjector) remove() {
+	logrus.StandardLogger().ReplaceHooks(f.hooks)
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseLogFields(t *testing.T) {
+	fields, err := parseLogFields("build=42, commit = abc123 ,query=a=b,")
+	if err != nil {
+		t.Fatalf("parseLogFields() unexpected error: %v", err)
+	}
+	expected := logrus.Fields{"build": "42", "commit": "abc123", "query": "a=b"}
+	if diff := cmp.Diff(expected, fields); diff != "" {
+		t.Errorf("parseLogFields() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, spec := range []string{"build", "=42"} {
+		if _, err := parseLogFields(spec); err == nil {
+			t.Errorf("parseLogFields(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestExecLogFields(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		LogFields:             "build=42,commit=abc123",
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	if len(hook.Entries) == 0 {
+		t.Fatalf("Expected log entries")
+	}
+	for _, entry := range hook.Entries {
+		if entry.Fields["build"] != "42" || entry.Fields["commit"] != "abc123" {
+			t.Errorf("Entry %q is missing the injected fields: %v", entry.Message, entry.Fields)
+		}
+	}
+
+	// The injector is removed once Exec returns
+	for _, h := range logrus.StandardLogger().Hooks[logrus.InfoLevel] {
+		if _, ok := h.(*fieldInjector); ok {
+			t.Errorf("Field injector hook was not removed")
+		}
+	}
+}
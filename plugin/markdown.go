@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// renderMarkdownSummary renders the aggregated results as a Markdown summary.
+func renderMarkdownSummary(agg *aggregate, args Args) string {
+	var b strings.Builder
+
+	title := "TestNG Results"
+	if args.Label != "" {
+		title += ": " + args.Label
+	}
+	res := agg.Results
+	fmt.Fprintf(&b, "## %s\n\n", title)
+	b.WriteString("| Total | Passed | Failures | Skips | Duration |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %.2f ms |\n",
+		res.Total, res.Total-res.Failures-res.Skipped, res.Failures, res.Skipped, res.DurationMS)
+
+	if len(agg.SuiteResults) > 0 {
+		b.WriteString("\n### Suites\n\n")
+		b.WriteString("| Suite | Total | Failures | Skips | Duration |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+		for _, name := range sortedSuiteNames(agg.SuiteResults) {
+			suite := agg.SuiteResults[name]
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %.2f ms |\n",
+				escapeMarkdownCell(name), suite.Total, suite.Failures, suite.Skipped, suite.DurationMS)
+		}
+	}
+
+	failedTests := agg.FailedTests
+	if args.AnonymizeNames {
+		failedTests = anonymizeNames(failedTests)
+	}
+	if len(failedTests) > 0 {
+		b.WriteString("\n### Failed Tests\n\n")
+		for _, name := range failedTests {
+			fmt.Fprintf(&b, "- `%s`\n", name)
+		}
+	}
+
+	return b.String()
+}
+
+// escapeMarkdownCell escapes pipes so a value can be placed in a table cell.
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// appendFile appends data to filename, creating the file if needed.
+func appendFile(filename string, data []byte) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeGitHubStepSummary appends the Markdown summary to the file named by
+// GITHUB_STEP_SUMMARY, which GitHub Actions renders on the run page.
+func writeGitHubStepSummary(filename string, agg *aggregate, args Args) error {
+	if err := appendFile(filename, []byte(renderMarkdownSummary(agg, args)+"\n")); err != nil {
+		return fmt.Errorf("failed to append GitHub step summary to %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteOutputsGitHubStepSummary(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "step_summary.md")
+	if err := os.WriteFile(summaryFile, []byte("Existing content\n"), 0644); err != nil {
+		t.Fatalf("Failed to write step summary: %v", err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryFile)
+
+	agg := newAggregate(0, false)
+	agg.add(fileReport{
+		Results:      Results{Total: 3, Failures: 1, DurationMS: 15},
+		SuiteResults: map[string]Results{"Suite1": {Total: 3, Failures: 1, DurationMS: 15}},
+	}, []string{"test1"}, nil)
+
+	if err := writeOutputs(Args{Label: "unit"}, agg); err != nil {
+		t.Fatalf("writeOutputs() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatalf("Failed to read step summary: %v", err)
+	}
+
+	expected := `Existing content
+## TestNG Results: unit
+
+| Total | Passed | Failures | Skips | Duration |
+| ---: | ---: | ---: | ---: | ---: |
+| 3 | 2 | 1 | 0 | 15.00 ms |
+
+### Suites
+
+| Suite | Total | Failures | Skips | Duration |
+| --- | ---: | ---: | ---: | ---: |
+| Suite1 | 3 | 1 | 0 | 15.00 ms |
+
+### Failed Tests
+
+- ` + "`test1`" + `
+
+`
+	if diff := cmp.Diff(expected, string(data)); diff != "" {
+		t.Errorf("Step summary mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderMarkdownSummaryAnonymizeNames(t *testing.T) {
+	agg := newAggregate(0, false)
+	agg.add(fileReport{Results: Results{Total: 1, Failures: 1}}, []string{"com.test.TestOne.test1"}, nil)
+
+	summary := renderMarkdownSummary(agg, Args{AnonymizeNames: true})
+	if strings.Contains(summary, "com.test.TestOne.test1") {
+		t.Errorf("Expected the failed test name to be anonymized, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "- `"+anonymizeName("com.test.TestOne.test1")+"`") {
+		t.Errorf("Expected the hashed failed test name, got:\n%s", summary)
+	}
+}
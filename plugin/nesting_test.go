@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDirectClassesUnderSuite(t *testing.T) {
+	const filename = "../testdata/nesting/testng-direct-classes.xml"
+	expected := Results{Total: 4, Failures: 1, Skipped: 1, DurationMS: 40, AssertionFailures: 1}
+
+	report, err := parseReport(filename, Args{ValidateSchema: true})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	var classes []string
+	for _, class := range report.Suites[0].Classes {
+		classes = append(classes, class.Name)
+	}
+	if diff := cmp.Diff([]string{"com.test.Accounts", "com.test.Transfers"}, classes); diff != "" {
+		t.Errorf("Classes mismatch (-want +got):\n%s", diff)
+	}
+
+	details := logTestNGReportDetails(report, Args{})
+	if diff := cmp.Diff(expected, details.Results); diff != "" {
+		t.Errorf("Results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"close"}, details.FailedTests); diff != "" {
+		t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+	}
+
+	streamed, err := processFile(filename, Args{StreamLargeFiles: true})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, streamed); diff != "" {
+		t.Errorf("Streamed results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMixedClassNesting(t *testing.T) {
+	report, err := parseReport("../testdata/nesting/testng-mixed-classes.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	details := logTestNGReportDetails(report, Args{})
+	if details.Results.Total != 2 || details.Results.Failures != 1 {
+		t.Errorf("Expected 2 tests and 1 failure, got %+v", details.Results)
+	}
+	if diff := cmp.Diff([]string{"test2"}, details.FailedTests); diff != "" {
+		t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// otlpMetricsPath is the OTLP/HTTP path metrics are posted to.
+const otlpMetricsPath = "/v1/metrics"
+
+// otlpScope is the instrumentation scope and service name of exported metrics.
+const otlpScope = "drone-testng"
+
+// The types below are the subset of the OTLP metrics data model needed to
+// export gauges, in the protobuf JSON encoding accepted by OTLP/HTTP.
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpInstrumentationScope `json:"scope"`
+	Metrics []otlpMetric             `json:"metrics"`
+}
+
+type otlpInstrumentationScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Unit        string    `json:"unit"`
+	Gauge       otlpGauge `json:"gauge"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+// otlpDataPoint holds either an integer or a double value. 64-bit integers
+// are encoded as strings in the protobuf JSON mapping.
+type otlpDataPoint struct {
+	TimeUnixNano string   `json:"timeUnixNano"`
+	AsInt        string   `json:"asInt,omitempty"`
+	AsDouble     *float64 `json:"asDouble,omitempty"`
+}
+
+// buildOTLPMetrics converts the results into an OTLP metrics request with one
+// gauge per count. The label is recorded as a resource attribute.
+func buildOTLPMetrics(results Results, label string, timestamp time.Time) otlpMetricsRequest {
+	ts := strconv.FormatInt(timestamp.UnixNano(), 10)
+	intGauge := func(name, description string, value int) otlpMetric {
+		return otlpMetric{
+			Name:        name,
+			Description: description,
+			Unit:        "{test}",
+			Gauge:       otlpGauge{DataPoints: []otlpDataPoint{{TimeUnixNano: ts, AsInt: strconv.Itoa(value)}}},
+		}
+	}
+	duration := results.DurationMS
+
+	return otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: otlpScope}},
+			{Key: "label", Value: otlpAnyValue{StringValue: label}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope: otlpInstrumentationScope{Name: otlpScope},
+			Metrics: []otlpMetric{
+				intGauge("testng.tests.total", "Total number of TestNG tests executed.", results.Total),
+				intGauge("testng.tests.failures", "Number of failed TestNG tests.", results.Failures),
+				intGauge("testng.tests.skipped", "Number of skipped TestNG tests.", results.Skipped),
+				{
+					Name:        "testng.tests.duration",
+					Description: "Total duration of TestNG tests in milliseconds.",
+					Unit:        "ms",
+					Gauge:       otlpGauge{DataPoints: []otlpDataPoint{{TimeUnixNano: ts, AsDouble: &duration}}},
+				},
+			},
+		}},
+	}}}
+}
+
+// otlpMetricsURL returns the metrics URL for endpoint. Like the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT, a base endpoint has /v1/metrics appended.
+func otlpMetricsURL(endpoint string) string {
+	if strings.HasSuffix(endpoint, otlpMetricsPath) {
+		return endpoint
+	}
+	return strings.TrimSuffix(endpoint, "/") + otlpMetricsPath
+}
+
+// exportOTLP posts the results as OTLP gauges to endpoint over HTTP. A
+// non-zero timeout bounds the whole request.
+func exportOTLP(ctx context.Context, endpoint string, results Results, label string, timeout time.Duration) error {
+	body, err := json.Marshal(buildOTLPMetrics(results, label, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to encode OTLP metrics: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	url := otlpMetricsURL(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request for %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export OTLP metrics to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to export OTLP metrics to %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExecOTLPEndpoint(t *testing.T) {
+	var received otlpMetricsRequest
+	var path, contentType string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode OTLP request: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		OTLPEndpoint:          collector.URL,
+		Label:                 "unit",
+		Timeout:               5 * time.Second,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	if path != "/v1/metrics" || contentType != "application/json" {
+		t.Errorf("Expected a JSON POST to /v1/metrics, got %s with %s", path, contentType)
+	}
+	if len(received.ResourceMetrics) != 1 || len(received.ResourceMetrics[0].ScopeMetrics) != 1 {
+		t.Fatalf("Expected one resource and scope, got %+v", received)
+	}
+
+	rm := received.ResourceMetrics[0]
+	if diff := cmp.Diff(otlpAttribute{Key: "label", Value: otlpAnyValue{StringValue: "unit"}}, rm.Resource.Attributes[1]); diff != "" {
+		t.Errorf("Label attribute mismatch (-want +got):\n%s", diff)
+	}
+
+	got := make(map[string]string)
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		point := metric.Gauge.DataPoints[0]
+		if point.AsDouble != nil {
+			got[metric.Name] = strconv.FormatFloat(*point.AsDouble, 'f', -1, 64)
+		} else {
+			got[metric.Name] = point.AsInt
+		}
+	}
+	expected := map[string]string{
+		"testng.tests.total":    "3",
+		"testng.tests.failures": "1",
+		"testng.tests.skipped":  "0",
+		"testng.tests.duration": "15",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Metrics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecOTLPEndpointFailure(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	tests := []struct {
+		name        string
+		failOnError bool
+		expectError bool
+	}{
+		{name: "Warn", failOnError: false, expectError: false},
+		{name: "Fail", failOnError: true, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				OTLPEndpoint:          collector.URL + "/v1/metrics",
+				FailOnOTLPError:       tc.failOnError,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
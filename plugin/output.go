@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeOutputs writes the configured report and notification files from the
+// aggregated results. When AnonymizeNames is set, class and test names are
+// hashed in every output.
+func writeOutputs(args Args, agg *aggregate) error {
+	reports := agg.Reports
+	if args.AnonymizeNames {
+		reports = anonymizeReports(reports)
+	}
+
+	if args.BazelXMLFile != "" {
+		if err := writeBazelXML(args.BazelXMLFile, reports, args); err != nil {
+			logrus.WithError(err).Error("Failed to write Bazel test XML")
+			return err
+		}
+		logrus.Infof("\nBazel test XML written to: %s", args.BazelXMLFile)
+	}
+
+	if args.JUnitFile != "" {
+		if err := writeJUnitXML(args.JUnitFile, reports, args); err != nil {
+			logrus.WithError(err).Error("Failed to write JUnit XML")
+			return err
+		}
+		logrus.Infof("\nJUnit XML written to: %s", args.JUnitFile)
+	}
+
+	if args.JUnitSplitBySuite {
+		paths, err := writeJUnitXMLBySuite(args.OutputDir, reports, args)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to write per-suite JUnit XML")
+			return err
+		}
+		logrus.Infof("\n%d per-suite JUnit XML files written to: %s", len(paths), args.OutputDir)
+	}
+
+	if args.AllureDir != "" {
+		count, err := writeAllureResults(args.AllureDir, reports, args)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to write Allure results")
+			return err
+		}
+		logrus.Infof("\n%d Allure results written to: %s", count, args.AllureDir)
+	}
+
+	if args.OutputFile != "" {
+		summary := buildSummary(agg.Results, reports, args)
+		if err := writeJSONSummary(args.OutputFile, summary); err != nil {
+			logrus.WithError(err).Error("Failed to write JSON summary")
+			return err
+		}
+		logrus.Infof("\nJSON summary written to: %s", args.OutputFile)
+	}
+
+	if args.JSONStdout {
+		if err := writeJSONStdout(buildSummary(agg.Results, reports, args)); err != nil {
+			logrus.WithError(err).Error("Failed to write JSON summary")
+			return err
+		}
+	}
+
+	if args.PromFile != "" {
+		if err := writePromFile(args.PromFile, agg.Results, args.Label); err != nil {
+			logrus.WithError(err).Error("Failed to write Prometheus metrics")
+			return err
+		}
+		logrus.Infof("\nPrometheus metrics written to: %s", args.PromFile)
+	}
+
+	if args.CSVFile != "" {
+		if err := writeCSV(args.CSVFile, reports); err != nil {
+			logrus.WithError(err).Error("Failed to write CSV file")
+			return err
+		}
+		logrus.Infof("\nCSV file written to: %s", args.CSVFile)
+	}
+
+	if args.TAPFile != "" {
+		if err := writeTAP(args.TAPFile, reports, args); err != nil {
+			logrus.WithError(err).Error("Failed to write TAP stream")
+			return err
+		}
+		logrus.Infof("\nTAP stream written to: %s", args.TAPFile)
+	}
+
+	if args.CanonicalReportFile != "" {
+		if err := writeCanonicalReport(args.CanonicalReportFile, reports); err != nil {
+			logrus.WithError(err).Error("Failed to write canonical report")
+			return err
+		}
+		logrus.Infof("\nCanonical report written to: %s", args.CanonicalReportFile)
+	}
+
+	if args.SlackMessageFile != "" {
+		if err := writeSlackMessage(args.SlackMessageFile, agg, args); err != nil {
+			logrus.WithError(err).Error("Failed to write Slack message")
+			return err
+		}
+		logrus.Infof("\nSlack message written to: %s", args.SlackMessageFile)
+	}
+
+	if summaryFile := os.Getenv("GITHUB_STEP_SUMMARY"); summaryFile != "" {
+		if err := writeGitHubStepSummary(summaryFile, agg, args); err != nil {
+			logrus.WithError(err).Error("Failed to write GitHub step summary")
+			return err
+		}
+		logrus.Infof("\nGitHub step summary written to: %s", summaryFile)
+	}
+
+	return nil
+}
+
+// writeRunRecords persists the run to the SQLite database, cache file and
+// history file. Later runs depend on these, so they are written on every run.
+// The cache is stored under key and skipped when no key could be computed.
+func writeRunRecords(args Args, agg *aggregate, key []byte) error {
+	if args.SQLiteFile != "" {
+		if err := writeSQLite(args.SQLiteFile, newRunRecord(agg.Results, args.Label, time.Now())); err != nil {
+			logrus.WithError(err).Error("Failed to write run to SQLite database")
+			return err
+		}
+		logrus.Infof("\nRun recorded in SQLite database: %s", args.SQLiteFile)
+	}
+
+	if args.CacheFile != "" && key != nil {
+		if err := writeCacheFile(args.CacheFile, key, agg.Results); err != nil {
+			logrus.WithError(err).Error("Failed to write cache file")
+			return err
+		}
+		logrus.Infof("\nResults cached to: %s", args.CacheFile)
+	}
+
+	if args.HistoryFile != "" {
+		if err := appendHistory(args.HistoryFile, newRunRecord(agg.Results, args.Label, time.Now())); err != nil {
+			logrus.WithError(err).Error("Failed to append run to history file")
+			return err
+		}
+		logrus.Infof("\nRun appended to history file: %s", args.HistoryFile)
+
+		if history, err := readHistory(args.HistoryFile); err != nil {
+			logrus.Warn(err.Error())
+		} else {
+			logrus.Infof("\nWeighted Failure Trend: %.2f%% over %d runs (half-life: %s)", WeightedTrend(history, args.TrendHalfLife), len(history), args.TrendHalfLife)
+		}
+	}
+
+	return nil
+}
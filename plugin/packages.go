@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPackage collects the classes without a package.
+const DefaultPackage = "(default)"
+
+// classPackage returns the Java package of a fully qualified class name.
+func classPackage(className string) string {
+	if idx := strings.LastIndex(className, "."); idx > 0 {
+		return className[:idx]
+	}
+	return DefaultPackage
+}
+
+// packageResults sums the class results of report by the package of each
+// class. suites holds the aggregated results of each suite of report, so the
+// package totals add up to the suite totals.
+func packageResults(report TestNGReport, suites []suiteAggregate) map[string]Results {
+	packages := make(map[string]Results)
+	for i, suite := range report.Suites {
+		for j, class := range suite.Classes {
+			pkg := classPackage(class.Name)
+			results := packages[pkg]
+			results.add(suites[i].ClassResults[j])
+			packages[pkg] = results
+		}
+	}
+	return packages
+}
+
+// logKeyedResults logs one summary block per key, e.g. per suite or package.
+func logKeyedResults(kind string, results map[string]Results, args Args) {
+	for _, key := range sortedSuiteNames(results) {
+		logrus.Infof("\n===============================================")
+		logrus.Infof("\nTotal Tests Results (%s: %s): %s", kind, key, formatResults(results[key], args))
+	}
+	if len(results) > 0 {
+		logrus.Infof("\n===============================================")
+	}
+}
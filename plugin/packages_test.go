@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAggregateByPackage(t *testing.T) {
+	expected := map[string]Results{
+		"com.shop.orders":   {Total: 3, Failures: 1, Skipped: 1, DurationMS: 15, AssertionFailures: 1, FullySkippedClasses: 1},
+		"com.shop.payments": {Total: 1, DurationMS: 20},
+		DefaultPackage:      {Total: 1, DurationMS: 1},
+	}
+
+	for _, stream := range []bool{false, true} {
+		args := Args{AggregateBy: AggregateByPackage, StreamLargeFiles: stream}
+		agg := processFiles([]string{"../testdata/buckets/testng-packages.xml"}, args)
+		if diff := cmp.Diff(expected, agg.Packages); diff != "" {
+			t.Errorf("Package results mismatch with StreamLargeFiles=%t (-want +got):\n%s", stream, diff)
+		}
+	}
+}
+
+func TestPackageTotalsMatchResults(t *testing.T) {
+	// The fixture has parameterized iterations and negative, NaN and
+	// implausible durations, which the run totals sanitize
+	args := Args{
+		AggregateBy:           AggregateByPackage,
+		CollapseParameterized: true,
+		MaxTestDuration:       Duration(time.Minute),
+	}
+	for _, stream := range []bool{false, true} {
+		args.StreamLargeFiles = stream
+		agg := processFiles([]string{"../testdata/buckets/testng-invalid-durations.xml"}, args)
+
+		var packages Results
+		for _, res := range agg.Packages {
+			packages.add(res)
+		}
+		if diff := cmp.Diff(agg.Results, packages); diff != "" {
+			t.Errorf("Package totals do not match the run results with StreamLargeFiles=%t (-want +got):\n%s", stream, diff)
+		}
+	}
+}
+
+func TestExecAggregateByPackageLogsBlocks(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/buckets/testng-packages.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		AggregateBy:           AggregateByPackage,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, entry := range hook.Entries {
+		if strings.HasPrefix(entry.Message, "\nTotal Tests Results (package:") {
+			got = append(got, entry.Message)
+		}
+	}
+	expected := []string{
+		"\nTotal Tests Results (package: (default)): 1 | Failures: 0 | Skips: 0 | Duration: 1.00 ms",
+		"\nTotal Tests Results (package: com.shop.orders): 3 | Failures: 1 | Skips: 1 | Duration: 15.00 ms",
+		"\nTotal Tests Results (package: com.shop.payments): 1 | Failures: 0 | Skips: 0 | Duration: 20.00 ms",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Package summary blocks mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"runtime"
+	"sync"
+)
+
+// suiteAggregate holds the aggregated results of a single suite and of each
+// of its classes, in order.
+type suiteAggregate struct {
+	Results      Results
+	ClassResults []Results
+	FailedTests  []string
+	SkippedTests []string
+}
+
+// aggregateSuites aggregates each suite of suites and returns the results in
+// suite order. With ParallelSuites set the suites are aggregated by a pool of
+// workers, so messages logged while aggregating classes may interleave, but
+// the returned order, and so the summaries logged from it, do not change.
+func aggregateSuites(suites []Suite, args Args) []suiteAggregate {
+	aggregates := make([]suiteAggregate, len(suites))
+	aggregateSuite := func(i int) {
+		results, classResults, failed, skipped := aggregateSuiteClassResults(suites[i], args)
+		aggregates[i] = suiteAggregate{Results: results, ClassResults: classResults, FailedTests: failed, SkippedTests: skipped}
+	}
+
+	if !args.ParallelSuites || len(suites) < 2 {
+		for i := range suites {
+			aggregateSuite(i)
+		}
+		return aggregates
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+	if workers > len(suites) {
+		workers = len(suites)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker writes only the indexes it receives
+			for i := range jobs {
+				aggregateSuite(i)
+			}
+		}()
+	}
+
+	for i := range suites {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return aggregates
+}
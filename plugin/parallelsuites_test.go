@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+// writeManySuitesReport writes a report with suites suites, each holding
+// classes classes of methods test methods, and returns its path.
+func writeManySuitesReport(tb testing.TB, suites, classes, methods int) string {
+	tb.Helper()
+	var sb strings.Builder
+	sb.WriteString("<testng-results>\n")
+	for s := 0; s < suites; s++ {
+		fmt.Fprintf(&sb, "<suite name=\"Suite%d\"><test name=\"Test%d\">\n", s, s)
+		for c := 0; c < classes; c++ {
+			fmt.Fprintf(&sb, "<class name=\"com.test.s%d.Class%d\">\n", s, c)
+			for m := 0; m < methods; m++ {
+				status := "PASS"
+				switch {
+				case (s+m)%7 == 0:
+					status = "SKIP"
+				case (s+c+m)%11 == 0:
+					status = "FAIL"
+				}
+				fmt.Fprintf(&sb, "<test-method name=\"test%d\" status=\"%s\" duration-ms=\"%d\"/>\n", m, status, m%5)
+			}
+			sb.WriteString("</class>\n")
+		}
+		sb.WriteString("</test></suite>\n")
+	}
+	sb.WriteString("</testng-results>\n")
+
+	filename := filepath.Join(tb.TempDir(), "testng-results.xml")
+	if err := os.WriteFile(filename, []byte(sb.String()), 0644); err != nil {
+		tb.Fatalf("Failed to write report: %v", err)
+	}
+	return filename
+}
+
+func TestLogTestNGReportDetailsParallelSuites(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	report, err := parseReport(writeManySuitesReport(t, 40, 3, 10), Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	run := func(args Args) (reportDetails, []string) {
+		hook := NewMockLogHook()
+		logrus.AddHook(hook)
+		details := logTestNGReportDetails(report, args)
+
+		var suites []string
+		for _, entry := range hook.Entries {
+			if strings.HasPrefix(entry.Message, "\nSuite: ") || strings.HasPrefix(entry.Message, "\nTotal Tests: ") {
+				suites = append(suites, entry.Message)
+			}
+		}
+		return details, suites
+	}
+
+	sequential, sequentialLogs := run(Args{})
+	for i := 0; i < 5; i++ {
+		parallel, parallelLogs := run(Args{ParallelSuites: true})
+		if diff := cmp.Diff(sequential, parallel); diff != "" {
+			t.Fatalf("Parallel details mismatch (-sequential +parallel):\n%s", diff)
+		}
+		if diff := cmp.Diff(sequentialLogs, parallelLogs); diff != "" {
+			t.Fatalf("Parallel suite summaries mismatch (-sequential +parallel):\n%s", diff)
+		}
+	}
+	if sequential.Results.Total != 40*3*10 || len(sequential.SuiteResults) != 40 {
+		t.Errorf("Unexpected results: %+v", sequential.Results)
+	}
+}
+
+// BenchmarkParallelSuites compares aggregating a report with many suites
+// sequentially and with a pool of workers.
+func BenchmarkParallelSuites(b *testing.B) {
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(os.Stderr)
+
+	report, err := parseReport(writeManySuitesReport(b, 100, 10, 20), Args{})
+	if err != nil {
+		b.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	for _, parallel := range []bool{false, true} {
+		name := "Sequential"
+		if parallel {
+			name = "Parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			args := Args{ParallelSuites: parallel}
+			for i := 0; i < b.N; i++ {
+				logTestNGReportDetails(report, args)
+			}
+		})
+	}
+}
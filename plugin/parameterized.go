@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"strconv"
+	"strings"
+)
+
+// collapseParameterized merges the iterations of parameterized methods into
+// a single test. tests must be the test methods of a single class, so tests
+// sharing a name are iterations of the same method. Retried attempts are
+// reruns rather than iterations and are kept as they are. The merged test
+// fails if any iteration failed, is skipped if any iteration was skipped and
+// none failed, and passes only if every iteration passed. Its duration is
+// the sum of the valid iteration durations.
+func collapseParameterized(tests []Test, failStatuses, skipStatuses map[string]bool) []Test {
+	type merged struct {
+		test     Test
+		duration float64
+		valid    bool
+	}
+
+	var order []*merged
+	byName := make(map[string]*merged)
+	for _, test := range tests {
+		if test.Retried {
+			order = append(order, &merged{test: test})
+			continue
+		}
+
+		m, ok := byName[test.Name]
+		if !ok {
+			m = &merged{test: test}
+			byName[test.Name] = m
+			order = append(order, m)
+		} else {
+			status := strings.ToUpper(test.Status)
+			current := strings.ToUpper(m.test.Status)
+			if failStatuses[status] || (skipStatuses[status] && !failStatuses[current]) {
+				m.test.Status = test.Status
+				m.test.Exception = test.Exception
+				m.test.ExceptionClass = test.ExceptionClass
+			}
+		}
+
+		if duration, err := strconv.ParseFloat(test.DurationMS, 64); err == nil {
+			m.duration += duration
+			m.valid = true
+		}
+	}
+
+	collapsed := make([]Test, 0, len(order))
+	for _, m := range order {
+		if m.valid {
+			m.test.DurationMS = strconv.FormatFloat(m.duration, 'f', -1, 64)
+		}
+		collapsed = append(collapsed, m.test)
+	}
+	return collapsed
+}
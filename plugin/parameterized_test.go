@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAggregateClassResultsCollapseParameterized(t *testing.T) {
+	class := Class{
+		Name: "com.test.Parameterized",
+		Tests: []Test{
+			{Name: "testAdd", Status: "PASS", DurationMS: "10"},
+			{Name: "testAdd", Status: "FAIL", DurationMS: "20", Exception: "java.lang.AssertionError: expected [3] but found [4]", ExceptionClass: "java.lang.AssertionError"},
+			{Name: "testAdd", Status: "PASS", DurationMS: "30"},
+			{Name: "testSubtract", Status: "PASS", DurationMS: "5"},
+			{Name: "testSubtract", Status: "PASS", DurationMS: "5"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected Results
+		failed   []string
+	}{
+		{
+			name:     "EachIteration",
+			args:     Args{},
+			expected: Results{Total: 5, Failures: 1, DurationMS: 70, AssertionFailures: 1},
+			failed:   []string{"testAdd"},
+		},
+		{
+			name:     "Collapsed",
+			args:     Args{CollapseParameterized: true},
+			expected: Results{Total: 2, Failures: 1, DurationMS: 70, AssertionFailures: 1},
+			failed:   []string{"testAdd"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, failed, _ := aggregateClassResults(class, tc.args)
+			if diff := cmp.Diff(tc.expected, results); diff != "" {
+				t.Errorf("aggregateClassResults() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.failed, failed); diff != "" {
+				t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCollapseParameterizedKeepsRetriedAttempts(t *testing.T) {
+	tests := []Test{
+		{Name: "testAdd", Status: "FAIL", DurationMS: "10", Retried: true},
+		{Name: "testAdd", Status: "PASS", DurationMS: "20"},
+		{Name: "testAdd", Status: "PASS", DurationMS: "30"},
+	}
+
+	expected := []Test{
+		{Name: "testAdd", Status: "FAIL", DurationMS: "10", Retried: true},
+		{Name: "testAdd", Status: "PASS", DurationMS: "50"},
+	}
+	failStatuses := statusSet("", DefaultFailStatuses)
+	skipStatuses := statusSet("", DefaultSkipStatuses)
+	if diff := cmp.Diff(expected, collapseParameterized(tests, failStatuses, skipStatuses)); diff != "" {
+		t.Errorf("collapseParameterized() mismatch (-want +got):\n%s", diff)
+	}
+}
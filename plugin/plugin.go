@@ -5,10 +5,15 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -20,26 +25,323 @@ const (
 	DefaultThresholdMode    = ThresholdModeAbsolute // Default value
 )
 
+// Number formats for summary logs
+const (
+	NumberFormatPlain   = "plain"
+	NumberFormatGrouped = "grouped"
+)
+
+// Granularities of the final summary
+const (
+	AggregateByAll     = "all"
+	AggregateBySuite   = "suite"
+	AggregateByPackage = "package"
+)
+
+// DefaultRootElement is the root element of TestNG XML reports
+const DefaultRootElement = "testng-results"
+
+// Default test-method statuses counted as failed and skipped
+const (
+	DefaultFailStatuses = "FAIL"
+	DefaultSkipStatuses = "SKIP,IGNORED"
+)
+
+// PassStatus is the test-method status of a passed test. Statuses that are
+// neither PassStatus nor a fail or skip status are counted as unknown.
+const PassStatus = "PASS"
+
 // Args represents the plugin's configurable arguments.
 type Args struct {
-	ReportFilenamePattern     string `envconfig:"PLUGIN_REPORT_FILENAME_PATTERN"`
-	FailedFails               int    `envconfig:"PLUGIN_FAILED_FAILS"`
-	FailedSkips               int    `envconfig:"PLUGIN_FAILED_SKIPS"`
-	FailureOnFailedTestConfig bool   `envconfig:"PLUGIN_FAILURE_ON_FAILED_TEST_CONFIG"`
-	ThresholdMode             string `envconfig:"PLUGIN_THRESHOLD_MODE"`
-	Level                     string `envconfig:"PLUGIN_LOG_LEVEL"`
+	ReportFilenamePattern      string        `envconfig:"PLUGIN_REPORT_FILENAME_PATTERN"`
+	FailedFails                int           `envconfig:"PLUGIN_FAILED_FAILS"`
+	FailedSkips                int           `envconfig:"PLUGIN_FAILED_SKIPS"`
+	FailedErrors               int           `envconfig:"PLUGIN_FAILED_ERRORS"`
+	FailureOnFailedTestConfig  bool          `envconfig:"PLUGIN_FAILURE_ON_FAILED_TEST_CONFIG"`
+	ThresholdMode              string        `envconfig:"PLUGIN_THRESHOLD_MODE"`
+	Level                      string        `envconfig:"PLUGIN_LOG_LEVEL"`
+	BazelXMLFile               string        `envconfig:"PLUGIN_BAZEL_XML_FILE"`
+	OutputFile                 string        `envconfig:"PLUGIN_OUTPUT_FILE"`
+	DetailedJSON               bool          `envconfig:"PLUGIN_DETAILED_JSON"`
+	ConfigFile                 string        `envconfig:"PLUGIN_CONFIG_FILE"`
+	MaxTestNames               int           `envconfig:"PLUGIN_MAX_TEST_NAMES" default:"1000"`
+	Label                      string        `envconfig:"PLUGIN_LABEL"`
+	PromFile                   string        `envconfig:"PLUGIN_PROM_FILE"`
+	ExpectedCounts             string        `envconfig:"PLUGIN_EXPECTED_COUNTS"`
+	WarnOnly                   bool          `envconfig:"PLUGIN_WARN_ONLY"`
+	UseSuiteDuration           bool          `envconfig:"PLUGIN_USE_SUITE_DURATION"`
+	FailStatuses               string        `envconfig:"PLUGIN_FAIL_STATUSES"`
+	SkipStatuses               string        `envconfig:"PLUGIN_SKIP_STATUSES"`
+	CSVFile                    string        `envconfig:"PLUGIN_CSV_FILE"`
+	FailOnZeroDuration         bool          `envconfig:"PLUGIN_FAIL_ON_ZERO_DURATION"`
+	NumberFormat               string        `envconfig:"PLUGIN_NUMBER_FORMAT"`
+	OpenRetries                int           `envconfig:"PLUGIN_OPEN_RETRIES"`
+	ReportByGroup              bool          `envconfig:"PLUGIN_REPORT_BY_GROUP"`
+	SQLiteFile                 string        `envconfig:"PLUGIN_SQLITE_FILE"`
+	MinMSPerTest               float64       `envconfig:"PLUGIN_MIN_MS_PER_TEST"`
+	FailOnMinMSPerTest         bool          `envconfig:"PLUGIN_FAIL_ON_MIN_MS_PER_TEST"`
+	AnonymizeNames             bool          `envconfig:"PLUGIN_ANONYMIZE_NAMES"`
+	PreferReportedCounts       bool          `envconfig:"PLUGIN_PREFER_REPORTED_COUNTS"`
+	FailOnWarnings             bool          `envconfig:"PLUGIN_FAIL_ON_WARNINGS"`
+	ThresholdRules             string        `envconfig:"PLUGIN_THRESHOLD_RULES"`
+	HighlightFirstFailure      bool          `envconfig:"PLUGIN_HIGHLIGHT_FIRST_FAILURE"`
+	ComparePattern             string        `envconfig:"PLUGIN_COMPARE_PATTERN"`
+	HideZeroMetrics            bool          `envconfig:"PLUGIN_HIDE_ZERO_METRICS"`
+	FileList                   string        `envconfig:"PLUGIN_FILE_LIST"`
+	MaxNamesLogged             int           `envconfig:"PLUGIN_MAX_NAMES_LOGGED" default:"50"`
+	HistoryFile                string        `envconfig:"PLUGIN_HISTORY_FILE"`
+	FailedConfigSkips          int           `envconfig:"PLUGIN_FAILED_CONFIG_SKIPS"`
+	ValidateSchema             bool          `envconfig:"PLUGIN_VALIDATE_SCHEMA"`
+	JSONStdout                 bool          `envconfig:"PLUGIN_JSON_STDOUT"`
+	WaitForReports             time.Duration `envconfig:"PLUGIN_WAIT_FOR_REPORTS"`
+	FailedAssertionFailures    int           `envconfig:"PLUGIN_FAILED_ASSERTION_FAILURES"`
+	FailedErrorFailures        int           `envconfig:"PLUGIN_FAILED_ERROR_FAILURES"`
+	TAPFile                    string        `envconfig:"PLUGIN_TAP_FILE"`
+	ReportOnly                 bool          `envconfig:"PLUGIN_REPORT_ONLY"`
+	MaxFullySkippedClasses     int           `envconfig:"PLUGIN_MAX_FULLY_SKIPPED_CLASSES"`
+	MaxPassRateDrop            float64       `envconfig:"PLUGIN_MAX_PASS_RATE_DROP"`
+	AccumulateFile             string        `envconfig:"PLUGIN_ACCUMULATE_FILE"`
+	FinalizeThreshold          bool          `envconfig:"PLUGIN_FINALIZE_THRESHOLD"`
+	RootElement                string        `envconfig:"PLUGIN_ROOT_ELEMENT" default:"testng-results"`
+	MaxDuration                Duration      `envconfig:"PLUGIN_MAX_DURATION"`
+	JUnitFile                  string        `envconfig:"PLUGIN_JUNIT_FILE"`
+	JUnitFailuresOnly          bool          `envconfig:"PLUGIN_JUNIT_FAILURES_ONLY"`
+	GroupByDescriptionPrefix   string        `envconfig:"PLUGIN_GROUP_BY_DESCRIPTION_PREFIX"`
+	NewestPerDir               bool          `envconfig:"PLUGIN_NEWEST_PER_DIR"`
+	MaxExceptionLength         int           `envconfig:"PLUGIN_MAX_EXCEPTION_LENGTH" default:"2000"`
+	CollapseParameterized      bool          `envconfig:"PLUGIN_COLLAPSE_PARAMETERIZED"`
+	SlackMessageFile           string        `envconfig:"PLUGIN_SLACK_MESSAGE_FILE"`
+	MinHealthScore             float64       `envconfig:"PLUGIN_MIN_HEALTH_SCORE"`
+	StreamLargeFiles           bool          `envconfig:"PLUGIN_STREAM_LARGE_FILES"`
+	OTLPEndpoint               string        `envconfig:"PLUGIN_OTLP_ENDPOINT"`
+	FailOnOTLPError            bool          `envconfig:"PLUGIN_FAIL_ON_OTLP_ERROR"`
+	Timeout                    time.Duration `envconfig:"PLUGIN_TIMEOUT" default:"30s"`
+	FailOnMissingException     bool          `envconfig:"PLUGIN_FAIL_ON_MISSING_EXCEPTION"`
+	SuiteAliases               string        `envconfig:"PLUGIN_SUITE_ALIASES"`
+	MinClasses                 int           `envconfig:"PLUGIN_MIN_CLASSES"`
+	StatusSymbols              bool          `envconfig:"PLUGIN_STATUS_SYMBOLS"`
+	ASCIIStatusSymbols         bool          `envconfig:"PLUGIN_ASCII_STATUS_SYMBOLS"`
+	ExpectUniformCounts        bool          `envconfig:"PLUGIN_EXPECT_UNIFORM_COUNTS"`
+	PostCommand                string        `envconfig:"PLUGIN_POST_COMMAND"`
+	FailOnPostCommand          bool          `envconfig:"PLUGIN_FAIL_ON_POST_COMMAND"`
+	MaxTestDuration            Duration      `envconfig:"PLUGIN_MAX_TEST_DURATION" default:"24h"`
+	SectionedOutput            bool          `envconfig:"PLUGIN_SECTIONED_OUTPUT"`
+	AllowJUnit                 bool          `envconfig:"PLUGIN_ALLOW_JUNIT"`
+	AggregateBy                string        `envconfig:"PLUGIN_AGGREGATE_BY"`
+	JUnitSplitBySuite          bool          `envconfig:"PLUGIN_JUNIT_SPLIT_BY_SUITE"`
+	OutputDir                  string        `envconfig:"PLUGIN_OUTPUT_DIR"`
+	ReportURL                  string        `envconfig:"PLUGIN_REPORT_URL"`
+	ReportURLToken             string        `envconfig:"PLUGIN_REPORT_URL_TOKEN" secret:"true"`
+	ReportURLUser              string        `envconfig:"PLUGIN_REPORT_URL_USER"`
+	ReportURLPass              string        `envconfig:"PLUGIN_REPORT_URL_PASS" secret:"true"`
+	MaxFlakyRate               float64       `envconfig:"PLUGIN_MAX_FLAKY_RATE"`
+	LogFields                  string        `envconfig:"PLUGIN_LOG_FIELDS"`
+	FailOnEmptyClasses         bool          `envconfig:"PLUGIN_FAIL_ON_EMPTY_CLASSES"`
+	TrendHalfLife              time.Duration `envconfig:"PLUGIN_TREND_HALF_LIFE" default:"168h"`
+	BaselinePassingFile        string        `envconfig:"PLUGIN_BASELINE_PASSING_FILE"`
+	AllureDir                  string        `envconfig:"PLUGIN_ALLURE_DIR"`
+	SuiteDurationTolerance     float64       `envconfig:"PLUGIN_SUITE_DURATION_TOLERANCE"`
+	MinAssertions              int           `envconfig:"PLUGIN_MIN_ASSERTIONS"`
+	CacheFile                  string        `envconfig:"PLUGIN_CACHE_FILE"`
+	BuildStartEnv              string        `envconfig:"PLUGIN_BUILD_START_ENV" default:"DRONE_BUILD_STARTED"`
+	FailOnStaleReport          bool          `envconfig:"PLUGIN_FAIL_ON_STALE_REPORT"`
+	LogSystemProperties        bool          `envconfig:"PLUGIN_LOG_SYSTEM_PROPERTIES"`
+	ParallelSuites             bool          `envconfig:"PLUGIN_PARALLEL_SUITES"`
+	UnstableFails              int           `envconfig:"PLUGIN_UNSTABLE_FAILS"`
+	UnstableSkips              int           `envconfig:"PLUGIN_UNSTABLE_SKIPS"`
+	FailOnUnknownStatus        bool          `envconfig:"PLUGIN_FAIL_ON_UNKNOWN_STATUS"`
+	CanonicalReportFile        string        `envconfig:"PLUGIN_CANONICAL_REPORT_FILE"`
+	MaxFlakyToFailRatio        float64       `envconfig:"PLUGIN_MAX_FLAKY_TO_FAIL_RATIO"`
+	AllowFlakyWithoutFailures  bool          `envconfig:"PLUGIN_ALLOW_FLAKY_WITHOUT_FAILURES"`
+	LimitFiles                 int           `envconfig:"PLUGIN_LIMIT_FILES"`
+	FollowSymlinks             bool          `envconfig:"PLUGIN_FOLLOW_SYMLINKS"`
+	MaxInvalidDurationRatio    float64       `envconfig:"PLUGIN_MAX_INVALID_DURATION_RATIO"`
+	FailOnInvalidDurationRatio bool          `envconfig:"PLUGIN_FAIL_ON_INVALID_DURATION_RATIO"`
+	OutputOnFailureOnly        bool          `envconfig:"PLUGIN_OUTPUT_ON_FAILURE_ONLY"`
+	TrimExceptions             bool          `envconfig:"PLUGIN_TRIM_EXCEPTIONS"`
+}
+
+// fileReport holds the parsed report and results of a single processed file.
+type fileReport struct {
+	Path         string
+	Report       TestNGReport
+	Results      Results
+	SuiteResults map[string]Results
+	Durations    durationStats
+	Buckets      map[string]Results
+
+	// ExecutedClasses holds the classes that executed a test. It is only
+	// populated when MinClasses is set.
+	ExecutedClasses []string
+
+	// Packages holds the results of each Java package. It is only populated
+	// when AggregateBy is package.
+	Packages map[string]Results
+
+	// SystemProperties holds the system properties found in the report as
+	// key=value pairs. It is only populated when LogSystemProperties is set.
+	SystemProperties []string
+
+	// FailedTests holds the names of the failed tests in the report. It is
+	// only populated when an output requires the parsed reports.
+	FailedTests []string
+
+	// Suites holds the results of each suite of Report, in order, so outputs
+	// do not aggregate the report again.
+	Suites []suiteAggregate
+}
+
+// reportDetails holds the aggregated results of a single report.
+type reportDetails struct {
+	Results      Results
+	FailedTests  []string
+	SkippedTests []string
+	SuiteResults map[string]Results
+
+	// Suites holds the results of each suite of the report, in order.
+	Suites []suiteAggregate
 }
 
 // ValidateInputs ensures the user inputs meet the plugin requirements.
 func ValidateInputs(args Args) error {
-	if args.ReportFilenamePattern == "" {
+	if args.ReportFilenamePattern == "" && args.FileList == "" && args.ReportURL == "" {
 		return errors.New("missing required parameter: ReportFilenamePattern. Please specify the pattern to locate the TestNG report files")
 	}
 
-	if args.FailedFails < 0 || args.FailedSkips < 0 {
+	if args.ReportURLToken != "" && args.ReportURLUser != "" {
+		return errors.New("ReportURLToken and ReportURLUser cannot be used together. Use either a Bearer token or basic auth")
+	}
+	if args.ReportURLPass != "" && args.ReportURLUser == "" {
+		return errors.New("ReportURLPass requires ReportURLUser for basic auth")
+	}
+
+	if args.FailedFails < 0 || args.FailedSkips < 0 || args.FailedErrors < 0 || args.FailedConfigSkips < 0 ||
+		args.FailedAssertionFailures < 0 || args.FailedErrorFailures < 0 || args.UnstableFails < 0 || args.UnstableSkips < 0 {
 		return errors.New("threshold values must be non-negative. Check the configured values for failed and skipped tests")
 	}
 
+	switch args.AggregateBy {
+	case "", AggregateByAll, AggregateBySuite, AggregateByPackage:
+	default:
+		return errors.New("invalid AggregateBy value. It must be 'all', 'suite' or 'package'. Check the configuration")
+	}
+
+	if args.NumberFormat != "" && args.NumberFormat != NumberFormatPlain && args.NumberFormat != NumberFormatGrouped {
+		return errors.New("invalid NumberFormat value. It must be 'plain' or 'grouped'. Check the configuration")
+	}
+
+	if _, err := parseExpectedCounts(args.ExpectedCounts); err != nil {
+		return err
+	}
+
+	if _, err := parseThresholdRules(args.ThresholdRules); err != nil {
+		return err
+	}
+
+	if _, err := parseSuiteAliases(args.SuiteAliases); err != nil {
+		return err
+	}
+
+	if _, err := parseLogFields(args.LogFields); err != nil {
+		return err
+	}
+
+	if args.GroupByDescriptionPrefix != "" {
+		if _, err := compileBucketPattern(args.GroupByDescriptionPrefix); err != nil {
+			return err
+		}
+	}
+
+	if args.MinAssertions < 0 {
+		return errors.New("MinAssertions must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.MinClasses < 0 {
+		return errors.New("MinClasses must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.MaxPassRateDrop < 0 {
+		return errors.New("MaxPassRateDrop must be non-negative. Use 0 to disable the check")
+	}
+	if args.MaxPassRateDrop > 0 && args.HistoryFile == "" {
+		return errors.New("MaxPassRateDrop requires HistoryFile to compare against the previous run")
+	}
+
+	if args.SuiteDurationTolerance < 0 {
+		return errors.New("SuiteDurationTolerance must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.TrendHalfLife < 0 {
+		return errors.New("TrendHalfLife must be non-negative. Use 0 to weight every run equally")
+	}
+
+	if args.Timeout < 0 {
+		return errors.New("Timeout must be non-negative. Use 0 for no timeout")
+	}
+
+	if args.MinHealthScore < 0 || args.MinHealthScore > 100 {
+		return errors.New("MinHealthScore must be between 0 and 100. Use 0 to disable the check")
+	}
+
+	if args.MaxFlakyRate < 0 || args.MaxFlakyRate > 100 {
+		return errors.New("MaxFlakyRate must be a percentage between 0 and 100. Use 0 to disable the check")
+	}
+
+	if args.MaxFlakyToFailRatio < 0 {
+		return errors.New("MaxFlakyToFailRatio must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.JUnitSplitBySuite && args.OutputDir == "" {
+		return errors.New("JUnitSplitBySuite requires OutputDir to write the per-suite files to")
+	}
+
+	if args.FinalizeThreshold && args.AccumulateFile == "" {
+		return errors.New("FinalizeThreshold requires AccumulateFile to read the accumulated results")
+	}
+
+	if args.MaxFullySkippedClasses < 0 {
+		return errors.New("MaxFullySkippedClasses must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.MaxTestDuration < 0 {
+		return errors.New("MaxTestDuration must be non-negative. Use 0 to disable the cap")
+	}
+	if args.MaxDuration < 0 {
+		return errors.New("MaxDuration must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.WaitForReports < 0 {
+		return errors.New("WaitForReports must be non-negative. Use 0 to disable waiting")
+	}
+
+	if args.OpenRetries < 0 {
+		return errors.New("OpenRetries must be non-negative. Use 0 to disable retries")
+	}
+
+	if args.MaxInvalidDurationRatio < 0 || args.MaxInvalidDurationRatio > 1 {
+		return errors.New("MaxInvalidDurationRatio must be a fraction between 0 and 1. Use 0 to disable the check")
+	}
+
+	if args.MinMSPerTest < 0 {
+		return errors.New("MinMSPerTest must be non-negative. Use 0 to disable the check")
+	}
+
+	if args.MaxExceptionLength < 0 {
+		return errors.New("MaxExceptionLength must be non-negative. Use 0 to log exceptions in full")
+	}
+
+	if args.MaxNamesLogged < 0 {
+		return errors.New("MaxNamesLogged must be non-negative. Use 0 to log all test names")
+	}
+
+	if args.LimitFiles < 0 {
+		return errors.New("LimitFiles must be non-negative. Use 0 to process all files")
+	}
+
+	if args.MaxTestNames < 0 {
+		return errors.New("MaxTestNames must be non-negative. Use 0 to record all test names")
+	}
+
 	if args.ThresholdMode == "" {
 		args.ThresholdMode = DefaultThresholdMode
 		logrus.Infof("PLUGIN_THRESHOLD_MODE not specified. Defaulting to '%s'", DefaultThresholdMode)
@@ -47,102 +349,448 @@ func ValidateInputs(args Args) error {
 		return errors.New("invalid ThresholdMode value. It must be 'absolute' or 'percentage'. Check the configuration")
 	}
 
+	if args.UnstableFails > 0 && args.FailedFails > 0 && args.UnstableFails >= args.FailedFails {
+		return fmt.Errorf("UnstableFails (%d) must be lower than FailedFails (%d)", args.UnstableFails, args.FailedFails)
+	}
+	if args.UnstableSkips > 0 && args.FailedSkips > 0 && args.UnstableSkips >= args.FailedSkips {
+		return fmt.Errorf("UnstableSkips (%d) must be lower than FailedSkips (%d)", args.UnstableSkips, args.FailedSkips)
+	}
+
+	if args.ThresholdMode == ThresholdModePercentage {
+		for _, threshold := range thresholdSettings(args) {
+			if threshold.Value > 100 {
+				return fmt.Errorf("%s (%d) must be between 0 and 100 in percentage mode", threshold.Name, threshold.Value)
+			}
+		}
+	}
+
 	return nil
 }
 
+// thresholdSetting is a named threshold setting.
+type thresholdSetting struct {
+	Name  string
+	Value int
+}
+
+// thresholdSettings returns the threshold settings that apply in both modes.
+func thresholdSettings(args Args) []thresholdSetting {
+	return []thresholdSetting{
+		{"FailedFails", args.FailedFails},
+		{"FailedSkips", args.FailedSkips},
+		{"FailedErrors", args.FailedErrors},
+		{"FailedConfigSkips", args.FailedConfigSkips},
+		{"FailedAssertionFailures", args.FailedAssertionFailures},
+		{"FailedErrorFailures", args.FailedErrorFailures},
+		{"UnstableFails", args.UnstableFails},
+		{"UnstableSkips", args.UnstableSkips},
+	}
+}
+
+// minPercentageLikeThreshold and percentageLikeRatio describe absolute
+// thresholds that were probably meant as percentages: at least 10, at most
+// 100, and at most 1% of the total number of tests.
+const (
+	minPercentageLikeThreshold = 10
+	percentageLikeRatio        = 100
+)
+
+// warnPercentageLikeThresholds warns about absolute thresholds that look like
+// they were meant as percentages, e.g. a FailedFails of 20 for 50000 tests.
+func warnPercentageLikeThresholds(results Results, args Args) {
+	for _, threshold := range thresholdSettings(args) {
+		if threshold.Value >= minPercentageLikeThreshold && threshold.Value <= 100 &&
+			results.Total >= threshold.Value*percentageLikeRatio {
+			logrus.Warnf("%s (%d) is an absolute number of tests, %.2f%% of the %d tests. Set ThresholdMode to percentage if it was meant as a rate",
+				threshold.Name, threshold.Value, float64(threshold.Value)/float64(results.Total)*100, results.Total)
+		}
+	}
+}
+
 // Exec handles TestNG XML report processing and logs details.
-func Exec(ctx context.Context, args Args) error {
-	files, err := locateFiles(args.ReportFilenamePattern)
-	if err != nil {
-		logger := logrus.WithError(err)
-		logger.Error("Error locating files")
-		return errors.New("failed to locate files: " + err.Error())
+func Exec(ctx context.Context, args Args) (err error) {
+	if args.LogFields != "" {
+		fields, err := parseLogFields(args.LogFields)
+		if err != nil {
+			return err
+		}
+		injector := installFieldInjector(fields)
+		defer injector.remove()
+	}
+
+	logrus.WithFields(effectiveConfig(args)).Debug("Effective configuration")
+
+	var warnings *warningCounter
+	if args.FailOnWarnings {
+		warnings = installWarningCounter()
+		defer warnings.remove()
+	}
+
+	var files []string
+	if args.ReportFilenamePattern != "" || args.FileList != "" || args.ReportURL == "" {
+		files, err = locateFiles(args.ReportFilenamePattern, args)
+		if err != nil {
+			logger := logrus.WithError(err)
+			logger.Error("Error locating files")
+			return errors.New("failed to locate files: " + err.Error())
+		}
+	}
+
+	if args.ReportURL != "" {
+		path, err := fetchReport(ctx, args.ReportURL, args, args.Timeout)
+		if err != nil {
+			logrus.WithError(err).Error("Error fetching report")
+			return err
+		}
+		defer os.Remove(path)
+		logrus.Infof("Fetched report from: %s", redactedURL(args.ReportURL))
+		files = append(files, path)
 	}
 
 	if len(files) == 0 {
 		return errors.New("no TestNG XML report files found. Check the report file pattern")
 	}
 
-	var (
-		resultsChan = make(chan Results, len(files))
-		errorsChan  = make(chan error, len(files))
-	)
-
-	for _, file := range files {
-		go func(f string) {
-			res, err := processFile(f)
+	// A cache hit restores the results of an earlier run of the same reports,
+	// which then go through every check as if the reports had been processed
+	var agg *aggregate
+	var key []byte
+	if args.CacheFile != "" {
+		if key, err = cacheKey(files, args); err != nil {
+			logrus.Warnf("Failed to compute the cache key: %v", err)
+		} else if cacheCovers(args) {
+			cached, ok, err := readCacheFile(args.CacheFile, key)
 			if err != nil {
-				errorsChan <- fmt.Errorf("failed to process file %s: %w", f, err)
-				return
+				logrus.Warn(err.Error())
 			}
-			resultsChan <- res
-		}(file)
-	}
-
-	var aggregatedResults Results
-	var skippedFiles []string
-
-	for i := 0; i < len(files); i++ {
-		select {
-		case res := <-resultsChan:
-			aggregatedResults.Total += res.Total
-			aggregatedResults.Failures += res.Failures
-			aggregatedResults.Skipped += res.Skipped
-			aggregatedResults.DurationMS += res.DurationMS
-		case err := <-errorsChan:
-			logrus.Warn(err)
-			if e, ok := err.(*os.PathError); ok {
-				skippedFiles = append(skippedFiles, e.Path)
+			if ok {
+				logrus.Infof("\nRestored results from cache file: %s", args.CacheFile)
+				agg = newAggregate(args.MaxTestNames, false)
+				agg.Results = cached
 			}
 		}
 	}
 
+	if agg == nil {
+		// Identical per-test warnings are logged once, after every file is processed
+		deduper := installWarningDeduper()
+		agg = processFiles(files, args)
+		deduper.flush()
+		deduper.remove()
+	}
+	aggregatedResults := agg.Results
+
 	// Log skipped files
-	if len(skippedFiles) > 0 {
-		logrus.Warnf("Skipped %d files due to errors: %v", len(skippedFiles), skippedFiles)
+	if len(agg.SkippedFiles) > 0 {
+		logrus.Warnf("Skipped %d files due to errors: %v", len(agg.SkippedFiles), agg.SkippedFiles)
+	}
+
+	if len(agg.SystemProperties) > 0 {
+		logSystemProperties(agg.SystemProperties)
+	}
+
+	for _, name := range duplicateSuiteNames(agg.SuiteFiles) {
+		logrus.Infof("Suite '%s' appears in %d files. Its per-file summaries are logged separately and combined in the suite totals", name, agg.SuiteFiles[name])
 	}
 
 	// Log aggregated results
 	logrus.Infof("\n===============================================")
-	logrus.Infof("\nTotal Tests Results: %d | Failures: %d | Skips: %d | Duration: %.2f ms", aggregatedResults.Total, aggregatedResults.Failures, aggregatedResults.Skipped, aggregatedResults.DurationMS)
+	logrus.Infof("\nTotal Tests Results: %s", formatResults(aggregatedResults, args))
+	if agg.Durations.Count > 0 {
+		logrus.Infof("\nMean: %.2f ms | StdDev: %.2f ms", agg.Durations.Mean(), agg.Durations.StdDev())
+	}
+	if len(files) > 1 {
+		if len(agg.FailedTests) > 0 {
+			logrus.Infof("\nAll Failed Test cases: %s", formatTestNames(agg.FailedTests, args.MaxNamesLogged))
+		}
+		if len(agg.SkippedTests) > 0 {
+			logrus.Infof("\nAll Skipped Test cases: %s", formatTestNames(agg.SkippedTests, args.MaxNamesLogged))
+		}
+		if agg.DroppedNames > 0 {
+			logrus.Infof("\n%d more test names not recorded (limit: %d)", agg.DroppedNames, args.MaxTestNames)
+		}
+	}
 	logrus.Infof("\n===============================================")
 
+	switch args.AggregateBy {
+	case AggregateBySuite:
+		logKeyedResults("suite", agg.SuiteResults, args)
+	case AggregateByPackage:
+		logKeyedResults("package", agg.Packages, args)
+	}
+
+	if len(agg.Buckets) > 0 {
+		logBucketResults(agg.Buckets, args)
+	}
+
+	// Read the previous run before this run is appended to the history
+	var previousRun *RunRecord
+	if args.MaxPassRateDrop > 0 {
+		if previousRun, err = lastRunRecord(args.HistoryFile); err != nil {
+			logrus.Error(err.Error())
+			return err
+		}
+	}
+
+	if err := writeRunRecords(args, agg, key); err != nil {
+		return err
+	}
+
+	// With OutputOnFailureOnly, outputs are held back until the outcome of
+	// the remaining checks is known and only written when the build fails
+	if args.OutputOnFailureOnly {
+		defer func() {
+			if err == nil {
+				logrus.Info("\nOutputOnFailureOnly is true and the build passed; skipping outputs")
+				return
+			}
+			// The build has already failed and writeOutputs logs its own errors
+			_ = writeOutputs(args, agg)
+		}()
+	} else if err := writeOutputs(args, agg); err != nil {
+		return err
+	}
+
+	if args.PostCommand != "" {
+		reports := agg.Reports
+		if args.AnonymizeNames {
+			reports = anonymizeReports(reports)
+		}
+		if err := runPostCommand(ctx, args.PostCommand, buildSummary(aggregatedResults, reports, args), args.Timeout); err != nil {
+			if args.FailOnPostCommand {
+				logrus.Error(err.Error())
+				return err
+			}
+			logrus.Warn(err.Error())
+		}
+	}
+
+	if args.OTLPEndpoint != "" {
+		if err := exportOTLP(ctx, args.OTLPEndpoint, aggregatedResults, args.Label, args.Timeout); err != nil {
+			if args.FailOnOTLPError {
+				logrus.Error(err.Error())
+				return err
+			}
+			logrus.Warn(err.Error())
+		} else {
+			logrus.Infof("\nMetrics exported to OTLP endpoint: %s", args.OTLPEndpoint)
+		}
+	}
+
+	if args.ComparePattern != "" {
+		diff, err := compareReports(agg.Reports, args)
+		if err != nil {
+			logrus.Error(err.Error())
+			return err
+		}
+		logReportDiff(diff, args.MaxNamesLogged)
+	}
+
+	if args.MinHealthScore > 0 {
+		logrus.Infof("\nHealth Score: %.2f", HealthScore(aggregatedResults))
+	}
+	if aggregatedResults.Flaky > 0 {
+		logrus.Infof("\nFlaky Rate: %.2f%% (%d of %d tests)", flakyRate(aggregatedResults), aggregatedResults.Flaky, aggregatedResults.Total)
+	}
+
+	// With an accumulator, thresholds apply to the totals of every invocation
+	// and only on the finalizing one
+	thresholdResults := aggregatedResults
+	checkThresholds := true
+	if args.AccumulateFile != "" {
+		accumulated, err := accumulateResults(args.AccumulateFile, aggregatedResults)
+		if err != nil {
+			logrus.Error(err.Error())
+			return err
+		}
+		logrus.Infof("\nAccumulated Tests Results: %s", formatResults(accumulated, args))
+		thresholdResults = accumulated
+		checkThresholds = args.FinalizeThreshold
+		if !checkThresholds {
+			logrus.Info("\nThreshold validation deferred to the invocation with FinalizeThreshold set")
+		}
+	}
+
+	// ReportOnly stops here, before any check that can fail the build
+	if args.ReportOnly {
+		logrus.Info("\nReportOnly is true; skipping threshold validation")
+		return nil
+	}
+
+	if err := checkPassRateDrop(previousRun, aggregatedResults, args.MaxPassRateDrop); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
+	if args.BaselinePassingFile != "" {
+		if err := checkBaselinePassing(agg.Reports, args); err != nil {
+			logrus.Error(err.Error())
+			recordFailReason(err)
+			return err
+		}
+	}
+
+	if args.ExpectedCounts != "" {
+		expected, err := parseExpectedCounts(args.ExpectedCounts)
+		if err != nil {
+			return err
+		}
+		if err := validateExpectedCounts(expected, agg.SuiteResults); err != nil {
+			if !args.WarnOnly {
+				logrus.Error(err.Error())
+				recordFailReason(err)
+				return err
+			}
+			logrus.Warn(err.Error())
+		}
+	}
+
+	if err := checkMinMSPerTest(agg.SuiteResults, args.MinMSPerTest); err != nil {
+		if args.FailOnMinMSPerTest {
+			logrus.Error(err.Error())
+			recordFailReason(err)
+			return err
+		}
+		logrus.Warn(err.Error())
+	}
+
+	if err := checkInvalidDurationRatio(aggregatedResults, args.MaxInvalidDurationRatio); err != nil {
+		if args.FailOnInvalidDurationRatio {
+			logrus.Error(err.Error())
+			recordFailReason(err)
+			return err
+		}
+		logrus.Warn(err.Error())
+	}
+
+	if args.ExpectUniformCounts {
+		if err := checkUniformCounts(agg.FileTotals); err != nil {
+			logrus.Error(err.Error())
+			recordFailReason(err)
+			return err
+		}
+	}
+
+	if err := checkMinClasses(agg.ExecutedClasses, args.MinClasses); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
+	if err := checkMinAssertions(aggregatedResults, args.MinAssertions); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
+	if err := checkMinHealthScore(aggregatedResults, args.MinHealthScore); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
+	if err := checkMaxFlakyRate(aggregatedResults, args.MaxFlakyRate); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
+	if err := checkMaxFlakyToFailRatio(aggregatedResults, args.MaxFlakyToFailRatio, args.AllowFlakyWithoutFailures); err != nil {
+		logrus.Error(err.Error())
+		recordFailReason(err)
+		return err
+	}
+
 	// Validate thresholds at the aggregate level
-	if err := validateThresholds(aggregatedResults, args); err != nil {
-		logger := logrus.WithFields(logrus.Fields{
-			"Total Tests": aggregatedResults.Total,
-			"Failures":    aggregatedResults.Failures,
-			"Skipped":     aggregatedResults.Skipped,
-			"DurationMS":  aggregatedResults.DurationMS,
-		})
-		logger.Error(err.Error())
+	if checkThresholds {
+		if err := validateThresholds(thresholdResults, args); err != nil {
+			logger := logrus.WithFields(logrus.Fields{
+				"Total Tests": thresholdResults.Total,
+				"Failures":    thresholdResults.Failures,
+				"Skipped":     thresholdResults.Skipped,
+				"Errors":      thresholdResults.Errors,
+				"DurationMS":  thresholdResults.DurationMS,
+			})
+			logger.Error(err.Error())
+			recordFailReason(err)
+			return err
+		}
+		markUnstable(thresholdResults, args)
+	}
+
+	if warnings != nil && warnings.Count() > 0 {
+		err := withFailReason(FailReasonWarnings, fmt.Errorf("\nbuild marked as failed as %d warning(s) were logged and FailOnWarnings is true", warnings.Count()))
+		logrus.Error(err.Error())
+		recordFailReason(err)
 		return err
 	}
 
 	return nil
 }
 
-// locateFiles identifies files matching the given pattern and checks read permissions.
-func locateFiles(pattern string) ([]string, error) {
-	// Use filepath.Glob to find files matching the pattern
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		logger := logrus.WithError(err).WithField("Pattern", pattern)
-		logger.Error("Error occurred while searching for files")
-		return nil, errors.New("failed to search for files: " + err.Error())
+// locateFiles identifies files matching the given pattern, plus any listed in
+// the FileList manifest, and checks read permissions. The pattern is ignored
+// when it is empty and a manifest is given. When nothing matches, the pattern
+// is globbed again until a file appears or WaitForReports elapses.
+// Environment variables in the pattern are expanded first.
+func locateFiles(pattern string, args Args) ([]string, error) {
+	pattern = expandPattern(pattern)
+	fileList := args.FileList
+
+	var matches []string
+	if pattern != "" || fileList == "" {
+		// Use filepath.Glob to find files matching the pattern
+		globbed, err := globWithWait(pattern, args.WaitForReports)
+		if err != nil {
+			logger := logrus.WithError(err).WithField("Pattern", pattern)
+			logger.Error("Error occurred while searching for files")
+			return nil, errors.New("failed to search for files: " + err.Error())
+		}
+
+		// Log the number of files found
+		logrus.Infof("Found %d files matching the pattern: %s", len(globbed), pattern)
+		matches = globbed
 	}
 
-	// Log the number of files found
-	logrus.Infof("Found %d files matching the pattern: %s", len(matches), pattern)
+	if fileList != "" {
+		listed, err := readFileList(fileList)
+		if err != nil {
+			logrus.WithError(err).WithField("FileList", fileList).Error("Error reading the file list")
+			return nil, err
+		}
+		logrus.Infof("Found %d files listed in: %s", len(listed), fileList)
+		matches = appendUnique(matches, listed)
+	}
 
 	if len(matches) == 0 {
 		return nil, errors.New("no files found matching the report filename pattern")
 	}
 
+	if args.NewestPerDir {
+		matches = newestPerDir(matches)
+		logrus.Infof("Kept %d files, the newest in each directory", len(matches))
+	}
+
 	// Check read permissions for each file
 	validFiles := []string{}
+	var dirs []string
 	for _, file := range matches {
+		// Symlinks may point outside the workspace, so they are only
+		// followed when explicitly allowed
+		if !args.FollowSymlinks {
+			if linkInfo, err := os.Lstat(file); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+				logrus.Warnf("Skipping symlinked report file: %s. Set FollowSymlinks to process it", file)
+				continue
+			}
+		}
 		if fileInfo, err := os.Stat(file); err == nil {
+			// os.Stat follows symlinks, so links to directories are skipped too
+			if fileInfo.IsDir() {
+				logrus.Debugf("Skipping directory matching the pattern: %s", file)
+				dirs = append(dirs, file)
+				continue
+			}
 			if fileInfo.Mode().Perm()&(1<<(uint(7))) != 0 {
 				validFiles = append(validFiles, file)
 			} else {
@@ -156,30 +804,49 @@ func locateFiles(pattern string) ([]string, error) {
 	// Log the number of readable files
 	logrus.Infof("Number of readable files: %d", len(validFiles))
 
+	if len(validFiles) == 0 && len(dirs) == len(matches) {
+		hint := filepath.Join(dirs[0], "*.xml")
+		logrus.Errorf("The pattern %s matched only directories. Did you mean %s?", pattern, hint)
+		return nil, fmt.Errorf("pattern matched only directories; did you mean %s?", hint)
+	}
+
 	if len(validFiles) == 0 {
 		return nil, errors.New("no readable files found matching the report filename pattern")
 	}
 
+	if args.LimitFiles > 0 && len(validFiles) > args.LimitFiles {
+		sort.Strings(validFiles)
+		logrus.Warnf("Processing only the first %d of %d files as LimitFiles is set; results are partial", args.LimitFiles, len(validFiles))
+		validFiles = validFiles[:args.LimitFiles]
+	}
+
 	return validFiles, nil
 }
 
 // processFile reads a TestNG XML report using xml.Decoder for streaming, validates its structure, and logs details.
-func processFile(filename string) (Results, error) {
-	logrus.Infof("Processing file: %s", filename)
+func processFile(filename string, args Args) (Results, error) {
+	if shouldStream(filename, args) {
+		fr, _, _, err := streamReport(filename, args)
+		if !errors.Is(err, errJUnitStream) {
+			return fr.Results, err
+		}
+		logrus.Infof("File %s is a JUnit report, which is not streamed; parsing it in full", filename)
+	}
 
-	// Open the file for streaming
-	file, err := os.Open(filename)
+	report, err := parseReport(filename, args)
 	if err != nil {
-		if os.IsNotExist(err) {
-			logrus.Errorf("File not found: %s", filename)
-			return Results{}, fmt.Errorf("file not found: %s", filename)
-		}
-		if os.IsPermission(err) {
-			logrus.Errorf("Permission denied for file: %s", filename)
-			return Results{}, fmt.Errorf("permission denied for file: %s", filename)
-		}
-		logrus.Errorf("Error opening file: %s. Error: %v", filename, err)
-		return Results{}, fmt.Errorf("error opening file: %s. Error: %v", filename, err)
+		return Results{}, err
+	}
+
+	// Log details and return results
+	return logTestNGReportDetails(report, args).Results, nil
+}
+
+// parseReport opens and decodes a TestNG XML report and validates its structure.
+func parseReport(filename string, args Args) (TestNGReport, error) {
+	file, root, err := openReport(filename, args)
+	if err != nil {
+		return TestNGReport{}, err
 	}
 	defer file.Close()
 
@@ -187,15 +854,23 @@ func processFile(filename string) (Results, error) {
 	decoder := xml.NewDecoder(file)
 	var report TestNGReport
 
-	if err := decoder.Decode(&report); err != nil {
+	if err := decodeRoot(decoder, root, args, &report); err != nil {
 		logrus.WithError(err).WithField("File", filename).Error("Failed to parse TestNG XML")
-		return Results{}, fmt.Errorf("failed to parse TestNG XML for file: %s. Error: %v", filename, err)
+		return TestNGReport{}, fmt.Errorf("failed to parse TestNG XML for file: %s. Error: %v", filename, err)
+	}
+
+	if args.SuiteAliases != "" {
+		aliases, err := parseSuiteAliases(args.SuiteAliases)
+		if err != nil {
+			return TestNGReport{}, err
+		}
+		applySuiteAliases(&report, aliases)
 	}
 
 	// Validate structure
 	if len(report.Suites) == 0 {
 		logrus.Infof("File %s contains no test suites in the XML structure", filename)
-		return Results{}, fmt.Errorf("no test suites found in the XML structure of file: %s", filename)
+		return TestNGReport{}, fmt.Errorf("no test suites found in the XML structure of file: %s", filename)
 	}
 
 	for _, suite := range report.Suites {
@@ -204,108 +879,534 @@ func processFile(filename string) (Results, error) {
 		}
 	}
 
-	// Log details and return results
-	return logTestNGReportDetails(report), nil
+	return report, nil
 }
 
-// logTestNGReportDetails logs the details of a TestNG report and returns the aggregated results.
-func logTestNGReportDetails(report TestNGReport) Results {
-	results := Results{}
-	var failedTests []string
-	var skippedTests []string
+// openReport opens a TestNG XML report, checking its element nesting when
+// ValidateSchema is set, and returns it with the name of its root element.
+func openReport(filename string, args Args) (*os.File, string, error) {
+	logrus.Infof("Processing file: %s", filename)
 
-	// Aggregate data across all suites
-	for _, suite := range report.Suites {
-		suiteResults, failed, skipped := aggregateSuiteResults(suite)
-		results.Total += suiteResults.Total
-		results.Failures += suiteResults.Failures
-		results.Skipped += suiteResults.Skipped
-		results.DurationMS += suiteResults.DurationMS
+	file, err := openWithRetry(filename, args.OpenRetries)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logrus.Errorf("File not found: %s", filename)
+			return nil, "", fmt.Errorf("file not found: %s", filename)
+		}
+		if os.IsPermission(err) {
+			logrus.Errorf("Permission denied for file: %s", filename)
+			return nil, "", fmt.Errorf("permission denied for file: %s", filename)
+		}
+		logrus.Errorf("Error opening file: %s. Error: %v", filename, err)
+		return nil, "", fmt.Errorf("error opening file: %s. Error: %v", filename, err)
+	}
 
-		failedTests = append(failedTests, failed...)
-		skippedTests = append(skippedTests, skipped...)
+	// A zero-byte file is usually a failed redirect and would otherwise
+	// surface as a confusing XML syntax error
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		file.Close()
+		logrus.Errorf("Empty report file: %s", filename)
+		return nil, "", fmt.Errorf("empty report file: %s", filename)
+	}
+
+	if err := checkStaleReport(file, args); err != nil {
+		file.Close()
+		logrus.Error(err.Error())
+		return nil, "", err
+	}
+
+	root := args.RootElement
+	if root == "" {
+		root = DefaultRootElement
+	}
+
+	if args.ValidateSchema {
+		if err := validateNesting(file, root, args.AllowJUnit); err != nil {
+			file.Close()
+			logrus.WithError(err).WithField("File", filename).Error("TestNG XML elements are not nested as in a TestNG results report")
+			return nil, "", fmt.Errorf("element nesting check failed for file: %s. Error: %v", filename, err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			file.Close()
+			return nil, "", fmt.Errorf("error rewinding file: %s. Error: %v", filename, err)
+		}
+	}
+
+	return file, root, nil
+}
+
+// expandPattern expands $VAR and ${VAR} references in pattern. Undefined
+// variables expand to an empty string with a warning.
+func expandPattern(pattern string) string {
+	return os.Expand(pattern, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			logrus.Warnf("Environment variable '%s' used in the report pattern is not defined", name)
+		}
+		return value
+	})
+}
+
+// reportPollInterval is the delay between globs while waiting for reports.
+// It is a variable so tests can shorten it.
+var reportPollInterval = 500 * time.Millisecond
+
+// globWithWait globs pattern, polling until it matches or wait elapses.
+func globWithWait(pattern string, wait time.Duration) ([]string, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		matches, err := filepath.Glob(pattern)
+		if err != nil || len(matches) > 0 || !time.Now().Before(deadline) {
+			return matches, err
+		}
+		logrus.Debugf("No files found matching the pattern: %s. Retrying in %s", pattern, reportPollInterval)
+		time.Sleep(reportPollInterval)
+	}
+}
+
+// openFile opens a report file. It is a variable so tests can simulate open failures.
+var openFile = os.Open
+
+// openRetryBackoff is the delay before the first retry of a transient open
+// error. It doubles after each attempt.
+var openRetryBackoff = 100 * time.Millisecond
+
+// decodeRoot scans the decoder's tokens for the first element named root and
+// decodes it into report. With AllowJUnit, a JUnit <testsuites> or
+// <testsuite> root is converted into report instead.
+func decodeRoot(decoder *xml.Decoder, root string, args Args, report *TestNGReport) error {
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("no <%s> element found", root)
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case start.Name.Local == root:
+			return decoder.DecodeElement(report, &start)
+		case args.AllowJUnit && isJUnitRoot(start.Name.Local):
+			return decodeJUnit(decoder, start, args, report)
+		}
+	}
+}
+
+// openWithRetry opens filename, retrying up to retries times with exponential
+// backoff when the error is transient. Not-found and permission errors are
+// returned immediately.
+func openWithRetry(filename string, retries int) (*os.File, error) {
+	backoff := openRetryBackoff
+	for attempt := 0; ; attempt++ {
+		file, err := openFile(filename)
+		if err == nil || attempt >= retries || !isTransientOpenError(err) {
+			return file, err
+		}
+
+		logrus.Warnf("Transient error opening file %s (attempt %d of %d): %v. Retrying in %s", filename, attempt+1, retries+1, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransientOpenError reports whether an open error is worth retrying.
+func isTransientOpenError(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR)
+}
+
+// logTestNGReportDetails logs the details of a TestNG report and returns the aggregated
+// results along with the failed and skipped test names and the results of each suite.
+func logTestNGReportDetails(report TestNGReport, args Args) reportDetails {
+	details := reportDetails{SuiteResults: make(map[string]Results)}
+
+	// Aggregate data across all suites, then log in suite order
+	aggregates := aggregateSuites(report.Suites, args)
+	details.Suites = aggregates
+	for i, suite := range report.Suites {
+		suiteResults, failed, skipped := aggregates[i].Results, aggregates[i].FailedTests, aggregates[i].SkippedTests
+		details.Results.add(suiteResults)
+
+		suiteTotal := details.SuiteResults[suite.Name]
+		suiteTotal.add(suiteResults)
+		details.SuiteResults[suite.Name] = suiteTotal
+
+		details.FailedTests = append(details.FailedTests, failed...)
+		details.SkippedTests = append(details.SkippedTests, skipped...)
 
 		// Log suite summary
-		logSuiteSummary(suite.Name, suiteResults)
+		logSuiteSummary(suite, suiteResults, args)
+		if args.ReportByGroup {
+			logGroupOutcomes(suite, args)
+		}
+		logConfigFailures(suite, args)
+		logDependencySkips(suite, args)
 		// Log groups and test details
 		logSuiteGroups(suite)
-		logSuiteTestDetails(suite)
+		logSuiteTestDetails(suite, args)
+	}
+
+	// Counts reported on the root element cover every suite in the report
+	if args.PreferReportedCounts {
+		details.Results = applyReportedCounts(details.Results, report.ReportedCounts)
 	}
 
-	// Log aggregated results with failed and skipped test names
+	logTestNames(details.FailedTests, details.SkippedTests, args.MaxNamesLogged)
+
+	return details
+}
+
+// logTestNames logs the names of the failed and skipped tests of a report.
+func logTestNames(failed, skipped []string, maxNames int) {
 	logrus.Infof("\n===============================================")
-	if len(failedTests) > 0 {
-		logrus.Infof("\nFailed Test cases: %s", formatTestNames(failedTests))
+	if len(failed) > 0 {
+		logrus.Infof("\nFailed Test cases: %s", formatTestNames(failed, maxNames))
 	}
-	if len(skippedTests) > 0 {
-		logrus.Infof("\nSkipped Test cases: %s", formatTestNames(skippedTests))
+	if len(skipped) > 0 {
+		logrus.Infof("\nSkipped Test cases: %s", formatTestNames(skipped, maxNames))
 	}
-
-	return results
 }
 
-// formatTestNames formats test names as a comma-separated string.
-func formatTestNames(names []string) string {
+// formatTestNames formats test names as a comma-separated string. At most
+// maxNames names are included, followed by "... and N more"; zero means no limit.
+func formatTestNames(names []string, maxNames int) string {
+	if maxNames > 0 && len(names) > maxNames {
+		return fmt.Sprintf("%s ... and %d more", strings.Join(names[:maxNames], ", "), len(names)-maxNames)
+	}
 	return strings.Join(names, ", ")
 }
 
-// aggregateSuiteResults aggregates test results for a suite.
-func aggregateSuiteResults(suite Suite) (Results, []string, []string) {
-	results := Results{}
+// aggregateSuiteClassResults aggregates test results for a suite and also
+// returns the results of each of its classes, in order.
+func aggregateSuiteClassResults(suite Suite, args Args) (Results, []Results, []string, []string) {
+	results := Results{Errors: suiteErrors(suite)}
+	classResults := make([]Results, 0, len(suite.Classes))
 	var failedTests []string
 	var skippedTests []string
 
 	for _, class := range suite.Classes {
-		classResults, failed, skipped := aggregateClassResults(class)
-		results.Total += classResults.Total
-		results.Failures += classResults.Failures
-		results.Skipped += classResults.Skipped
-		results.DurationMS += classResults.DurationMS
+		res, failed, skipped := aggregateClassResults(class, args)
+		results.add(res)
+		classResults = append(classResults, res)
 
 		failedTests = append(failedTests, failed...)
 		skippedTests = append(skippedTests, skipped...)
 	}
 
-	return results, failedTests, skippedTests
+	return finishSuiteResults(suite, results, args), classResults, failedTests, skippedTests
+}
+
+// finishSuiteResults applies the suite-level settings to the results summed
+// from the suite's classes: the duration consistency check when
+// SuiteDurationTolerance is set, the suite duration when UseSuiteDuration is
+// set and the reported counts when PreferReportedCounts is set.
+func finishSuiteResults(suite Suite, results Results, args Args) Results {
+	if args.SuiteDurationTolerance > 0 {
+		checkSuiteDuration(suite, results.DurationMS, args.SuiteDurationTolerance)
+	}
+
+	if args.UseSuiteDuration {
+		if duration, ok := suiteDuration(suite); ok {
+			results.DurationMS = duration
+		}
+	}
+
+	if args.PreferReportedCounts {
+		results = applyReportedCounts(results, suite.ReportedCounts)
+	}
+
+	return results
+}
+
+// checkSuiteDuration warns when the summed test durations of a suite differ
+// from the duration-ms declared on the suite element by more than tolerance
+// percent of the declared duration. Suites running tests in parallel can
+// legitimately sum to more than their wall-clock duration.
+func checkSuiteDuration(suite Suite, summed, tolerance float64) {
+	declared, err := strconv.ParseFloat(suite.Duration, 64)
+	if err != nil || declared <= 0 {
+		return
+	}
+
+	if deviation := math.Abs(summed-declared) / declared * 100; deviation > tolerance {
+		logrus.Warnf("Suite '%s' test durations sum to %.2f ms but the suite declares %.2f ms, a %.2f%% difference exceeding SuiteDurationTolerance (%.2f%%)", suite.Name, summed, declared, deviation, tolerance)
+	}
+}
+
+// suiteErrors returns the errors reported on the suite element, falling back
+// to the sum of the errors reported on its <test> elements. Invalid counts
+// are warned about and treated as no errors.
+func suiteErrors(suite Suite) int {
+	errors, err := parseErrorCount(suite.Errors)
+	if err != nil {
+		warnf("Suite '%s': %v", suite.Name, err)
+	}
+	if errors > 0 {
+		return errors
+	}
+	for _, test := range suite.Tests {
+		count, err := parseErrorCount(test.Errors)
+		if err != nil {
+			warnf("Test '%s' in suite '%s': %v", test.Name, suite.Name, err)
+		}
+		errors += count
+	}
+	return errors
+}
+
+// parseErrorCount parses an errors attribute. A missing attribute counts as
+// no errors; an invalid one returns 0 and an error.
+func parseErrorCount(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("ignoring invalid errors count '%s'", value)
+	}
+	return count, nil
+}
+
+// suiteDuration returns the duration of a suite from the durations of its
+// <test> elements, falling back to the duration-ms of the suite element.
+// It reports false when neither carries a valid duration.
+func suiteDuration(suite Suite) (float64, bool) {
+	var total float64
+	found := false
+	for _, test := range suite.Tests {
+		duration, err := strconv.ParseFloat(test.DurationMS, 64)
+		if err != nil {
+			continue
+		}
+		total += duration
+		found = true
+	}
+	if found {
+		return total, true
+	}
+
+	duration, err := strconv.ParseFloat(suite.Duration, 64)
+	if err != nil {
+		return 0, false
+	}
+	return duration, true
 }
 
 // aggregateClassResults aggregates test results for a class.
-func aggregateClassResults(class Class) (Results, []string, []string) {
+func aggregateClassResults(class Class, args Args) (Results, []string, []string) {
 	results := Results{}
 	var failedTests []string
 	var skippedTests []string
 
-	for _, test := range class.Tests {
-		results.Total++
-		if test.Status == "FAIL" {
-			results.Failures++
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	tests := class.Tests
+	if args.CollapseParameterized {
+		tests = collapseParameterized(tests, failStatuses, skipStatuses)
+	}
+
+	for _, test := range tests {
+		countTest(&results, test, class.Name, failStatuses, skipStatuses, args.MaxTestDuration, warnf)
+		status := strings.ToUpper(test.Status)
+		if test.Retried {
+			continue
+		} else if failStatuses[status] {
 			failedTests = append(failedTests, test.Name)
-		} else if test.Status == "SKIP" {
-			results.Skipped++
+		} else if skipStatuses[status] {
 			skippedTests = append(skippedTests, test.Name)
 		}
+	}
 
-		// Handle invalid or missing DurationMS
-		duration, err := strconv.ParseFloat(test.DurationMS, 64)
-		if err != nil {
-			logrus.Warnf("Invalid or missing DurationMS for test '%s': %v", test.Name, err)
+	analyzers := retryAnalyzers(class.Tests)
+	for _, name := range flakyTests(class.Tests, failStatuses, skipStatuses) {
+		results.Flaky++
+		if analyzer, ok := analyzers[name]; ok {
+			logrus.Infof("Flaky test '%s' in class '%s' passed after a retried attempt with retry analyzer '%s'", name, class.Name, analyzer)
 			continue
 		}
-		results.DurationMS += duration
+		logrus.Infof("Flaky test '%s' in class '%s' passed after a retried attempt", name, class.Name)
+	}
+
+	// A class with no test methods had them all deselected
+	if len(class.Tests) == 0 {
+		results.EmptyClasses++
+		logrus.Infof("\nClass with no executed methods: %s", class.Name)
+	}
+
+	// A class with every test skipped is usually disabled
+	if results.Total > 0 && results.Skipped == results.Total {
+		results.FullySkippedClasses++
+		logrus.Infof("\nEntirely skipped class: %s", class.Name)
 	}
 
 	return results, failedTests, skippedTests
 }
 
-// logSuiteSummary logs a summary for a suite.
-func logSuiteSummary(suiteName string, results Results) {
+// countTest adds the outcome, assertions and duration of a single test of
+// className to results. Problems with the test are reported through warn, so
+// callers that count the same tests again can pass a no-op. A retried attempt
+// only adds its duration: the attempt that ran last carries the outcome, so
+// a test counts once however often it was retried.
+func countTest(results *Results, test Test, className string, failStatuses, skipStatuses map[string]bool, maxDuration Duration, warn func(format string, args ...interface{})) {
+	if test.Retried {
+		countDuration(results, test, maxDuration, warn)
+		return
+	}
+
+	results.Total++
+	status := strings.ToUpper(test.Status)
+	if failStatuses[status] {
+		results.Failures++
+		switch classifyFailure(test) {
+		case failureAssertion:
+			results.AssertionFailures++
+		case failureError:
+			results.ErrorFailures++
+		}
+		// A failure without exception text usually means the report is broken
+		if strings.TrimSpace(test.Exception) == "" {
+			results.MissingExceptions++
+			warn("Test '%s' in class '%s' failed without an exception", test.Name, className)
+		}
+	} else if skipStatuses[status] {
+		results.Skipped++
+		if test.IsConfig {
+			results.ConfigSkips++
+		}
+	} else if status != PassStatus {
+		results.Unknown++
+		warn("Test '%s' in class '%s' has unrecognized status '%s'", test.Name, className, test.Status)
+	}
+
+	if test.Assertions != "" {
+		if count, err := strconv.Atoi(strings.TrimSpace(test.Assertions)); err == nil && count >= 0 {
+			results.Assertions += count
+			results.AssertionsReported++
+		} else {
+			warn("Invalid assertions count '%s' for test '%s'", test.Assertions, test.Name)
+		}
+	}
+
+	countDuration(results, test, maxDuration, warn)
+}
+
+// countDuration adds the duration of test to results, counting it as invalid
+// when it is missing, malformed, negative or NaN.
+func countDuration(results *Results, test Test, maxDuration Duration, warn func(format string, args ...interface{})) {
+	// Handle invalid or missing DurationMS
+	duration, err := strconv.ParseFloat(test.DurationMS, 64)
+	if err != nil {
+		results.InvalidDurations++
+		warn("Invalid or missing DurationMS for test '%s': %v", test.Name, err)
+		return
+	}
+	if math.IsNaN(duration) || duration < 0 {
+		results.InvalidDurations++
+	}
+	results.DurationMS += sanitizeDuration(test.Name, duration, maxDuration, warn)
+}
+
+// noWarn discards warnings, for counting tests whose warnings were already logged.
+func noWarn(string, ...interface{}) {}
+
+// sanitizeDuration guards the totals against malformed durations: negative
+// and NaN durations count as zero and durations above maxDuration are capped
+// to it, with a warning. A zero maxDuration disables the cap.
+func sanitizeDuration(name string, duration float64, maxDuration Duration, warn func(format string, args ...interface{})) float64 {
+	switch {
+	case math.IsNaN(duration) || duration < 0:
+		warn("Invalid DurationMS %s for test '%s'; counting it as 0 ms", strconv.FormatFloat(duration, 'f', -1, 64), name)
+		return 0
+	case maxDuration > 0 && duration > maxDuration.Milliseconds():
+		warn("Implausible DurationMS %s for test '%s'; capping it to MaxTestDuration (%s)", strconv.FormatFloat(duration, 'f', -1, 64), name, maxDuration)
+		return maxDuration.Milliseconds()
+	}
+	return duration
+}
+
+// statusSet parses a comma-separated list of statuses into a set, using
+// defaults when the list is empty. Statuses are compared case-insensitively.
+func statusSet(statuses, defaults string) map[string]bool {
+	if strings.TrimSpace(statuses) == "" {
+		statuses = defaults
+	}
+
+	set := make(map[string]bool)
+	for _, status := range strings.Split(statuses, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			set[strings.ToUpper(status)] = true
+		}
+	}
+	return set
+}
+
+// logSuiteSummary logs a summary for a suite, including its parallelism settings.
+func logSuiteSummary(suite Suite, results Results, args Args) {
 	logrus.Infof("\n===============================================")
-	logrus.Infof("\nSuite: %s", suiteName)
-	logrus.Infof("\nTotal Tests: %d | Failures: %d | Skips: %d | Duration: %.2f ms",
-		results.Total, results.Failures, results.Skipped, results.DurationMS)
+	logrus.Infof("\nSuite: %s", suite.Name)
+	logrus.Infof("\nTotal Tests: %s", formatResults(results, args))
+	logrus.Infof("\nParallel: %s | Thread Count: %s", valueOrUnset(suite.Parallel), valueOrUnset(suite.ThreadCount))
 	logrus.Infof("\n===============================================")
 }
 
+// valueOrUnset returns value, or "unset" when it is empty.
+func valueOrUnset(value string) string {
+	if value == "" {
+		return "unset"
+	}
+	return value
+}
+
+// formatResults formats results for summary lines, e.g.
+// "10 | Failures: 2 | Skips: 1 | Duration: 100.00 ms". The error count is
+// omitted when no errors were reported, and with HideZeroMetrics so are zero
+// failure and skip counts.
+func formatResults(results Results, args Args) string {
+	parts := []string{formatCount(results.Total, args.NumberFormat)}
+	if results.Failures > 0 || !args.HideZeroMetrics {
+		parts = append(parts, "Failures: "+formatCount(results.Failures, args.NumberFormat))
+	}
+	if results.Errors > 0 {
+		parts = append(parts, "Errors: "+formatCount(results.Errors, args.NumberFormat))
+	}
+	if results.Skipped > 0 || !args.HideZeroMetrics {
+		parts = append(parts, "Skips: "+formatCount(results.Skipped, args.NumberFormat))
+	}
+	if results.Unknown > 0 {
+		parts = append(parts, "Unknown: "+formatCount(results.Unknown, args.NumberFormat))
+	}
+	parts = append(parts, fmt.Sprintf("Duration: %.2f ms", results.DurationMS))
+	return strings.Join(parts, " | ")
+}
+
+// formatCount formats a count according to the number format. The grouped
+// format inserts thousands separators, e.g. 1000000 becomes 1,000,000.
+func formatCount(n int, numberFormat string) string {
+	s := strconv.Itoa(n)
+	if numberFormat != NumberFormatGrouped {
+		return s
+	}
+
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+
+	var b strings.Builder
+	for i, digit := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
 // logSuiteGroups logs group details for a suite.
 func logSuiteGroups(suite Suite) {
 	logrus.Infof("\nGroups:")
@@ -317,40 +1418,144 @@ func logSuiteGroups(suite Suite) {
 	}
 }
 
-// logSuiteTestDetails logs test details for a suite.
-func logSuiteTestDetails(suite Suite) {
+// logSuiteTestDetails logs test details for a suite, prefixed with a status
+// symbol when StatusSymbols is set. With SectionedOutput, tests are grouped
+// into Failures, Skips and Passes sections instead of report order.
+func logSuiteTestDetails(suite Suite, args Args) {
 	logrus.Infof("\nTest Details:")
+	if !args.SectionedOutput {
+		for _, class := range suite.Classes {
+			for _, test := range class.Tests {
+				logTestDetail(test, args)
+			}
+		}
+		return
+	}
+
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	var failures, skips, passes []Test
 	for _, class := range suite.Classes {
 		for _, test := range class.Tests {
-			logrus.Infof("\n- Test: %s | Status: %s | Duration: %s ms", test.Name, test.Status, test.DurationMS)
-			if test.Status == "FAIL" && test.Exception != "" {
-				logrus.Infof("\n    Exception: %s", test.Exception)
+			status := strings.ToUpper(test.Status)
+			switch {
+			case failStatuses[status]:
+				failures = append(failures, test)
+			case skipStatuses[status]:
+				skips = append(skips, test)
+			default:
+				passes = append(passes, test)
 			}
 		}
 	}
+
+	for _, section := range []struct {
+		title string
+		tests []Test
+	}{
+		{"Failures", failures},
+		{"Skips", skips},
+		{"Passes", passes},
+	} {
+		if len(section.tests) == 0 {
+			continue
+		}
+		logrus.Infof("\n%s (%d):", section.title, len(section.tests))
+		for _, test := range section.tests {
+			logTestDetail(test, args)
+		}
+	}
+}
+
+// logTestDetail logs a single test and, for failures, its exception.
+// With TrimExceptions, the exception is collapsed onto a single line first.
+// Exceptions longer than MaxExceptionLength characters are truncated; zero
+// means no limit.
+func logTestDetail(test Test, args Args) {
+	prefix := "- "
+	if args.StatusSymbols {
+		prefix += statusSymbol(test.Status, args) + " "
+	}
+	logrus.Infof("\n%sTest: %s | Status: %s | Duration: %s ms", prefix, test.Name, test.Status, test.DurationMS)
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	if failStatuses[strings.ToUpper(test.Status)] && test.Exception != "" {
+		exception := test.Exception
+		if args.TrimExceptions {
+			exception = collapseWhitespace(exception)
+		}
+		logrus.Infof("\n    Exception: %s", truncateText(exception, args.MaxExceptionLength))
+	}
+}
+
+// collapseWhitespace trims s and replaces each run of whitespace, including
+// newlines, with a single space.
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateText truncates s to maxLength characters followed by a
+// "...(truncated)" marker. Zero means no limit.
+func truncateText(s string, maxLength int) string {
+	if maxLength <= 0 || len(s) <= maxLength {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLength {
+		return s
+	}
+	return string(runes[:maxLength]) + "...(truncated)"
 }
 
 // validateThresholds validates test report thresholds based on aggregate results.
 func validateThresholds(results Results, args Args) error {
-
 	if args.FailureOnFailedTestConfig && results.Failures > 0 {
-		return errors.New("\nbuild marked as failed due to failed configuration methods as FailureOnFailedTestConfig is true")
+		return withFailReason(FailReasonConfigFailure, errors.New("\nbuild marked as failed due to failed configuration methods as FailureOnFailedTestConfig is true"))
+	}
+
+	if args.FailOnZeroDuration && results.Total > 0 && results.DurationMS == 0 {
+		return withFailReason(FailReasonZeroDuration, fmt.Errorf("\nbuild marked as failed as %d tests reported a total duration of 0 ms, which suggests they did not actually execute", results.Total))
+	}
+
+	if args.FailOnMissingException && results.MissingExceptions > 0 {
+		return withFailReason(FailReasonMissingException, fmt.Errorf("\nbuild marked as failed as %d failed tests have no exception as FailOnMissingException is true", results.MissingExceptions))
+	}
+
+	if args.MaxFullySkippedClasses > 0 && results.FullySkippedClasses > args.MaxFullySkippedClasses {
+		return withFailReason(FailReasonSkippedClasses, fmt.Errorf("\nbuild marked as failed as %d classes were entirely skipped, exceeding MaxFullySkippedClasses (%d)", results.FullySkippedClasses, args.MaxFullySkippedClasses))
+	}
+
+	if args.FailOnEmptyClasses && results.EmptyClasses > 0 {
+		return withFailReason(FailReasonEmptyClasses, fmt.Errorf("\nbuild marked as failed as %d classes have no executed methods as FailOnEmptyClasses is true", results.EmptyClasses))
+	}
+
+	if args.FailOnUnknownStatus && results.Unknown > 0 {
+		return withFailReason(FailReasonUnknownStatus, fmt.Errorf("\nbuild marked as failed as %d tests have an unrecognized status as FailOnUnknownStatus is true", results.Unknown))
+	}
+
+	if args.MaxDuration > 0 && results.DurationMS > args.MaxDuration.Milliseconds() {
+		return withFailReason(FailReasonMaxDuration, fmt.Errorf("\nbuild marked as failed as the total test duration (%.2f ms) exceeded MaxDuration (%s)", results.DurationMS, args.MaxDuration))
 	}
 
 	switch args.ThresholdMode {
 	case ThresholdModeAbsolute: // Absolute thresholds
+		warnPercentageLikeThresholds(results, args)
 		if err := validateAbsoluteThresholds(results, args); err != nil {
-			return errors.New("\nabsolute threshold validation failed: " + err.Error())
+			return fmt.Errorf("\nabsolute threshold validation failed: %w", err)
 		}
 
 	case ThresholdModePercentage: // Percentage thresholds
 		if err := validatePercentageThresholds(results, args); err != nil {
-			return errors.New("\npercentage threshold validation failed: " + err.Error())
+			return fmt.Errorf("\npercentage threshold validation failed: %w", err)
 		}
 
 	default:
 		return fmt.Errorf("\ninvalid ThresholdMode: %s, expected 1 (absolute) or 2 (percentage)", args.ThresholdMode)
 	}
+
+	if err := validateThresholdRules(results, args.ThresholdRules); err != nil {
+		return withFailReason(FailReasonThresholdRule, errors.New("\nthreshold rules validation failed: "+err.Error()))
+	}
 	return nil
 }
 
@@ -368,10 +1573,22 @@ func checkThreshold(metricName string, actualValue float64, thresholdValue float
 // validateAbsoluteThresholds checks absolute thresholds using the helper function.
 func validateAbsoluteThresholds(results Results, args Args) error {
 	if err := checkThreshold("failed", float64(results.Failures), float64(args.FailedFails), false); err != nil {
-		return err
+		return withFailReason(FailReasonAbsoluteFailures, err)
 	}
 	if err := checkThreshold("skipped", float64(results.Skipped), float64(args.FailedSkips), false); err != nil {
-		return err
+		return withFailReason(FailReasonSkips, err)
+	}
+	if err := checkThreshold("errored", float64(results.Errors), float64(args.FailedErrors), false); err != nil {
+		return withFailReason(FailReasonErrors, err)
+	}
+	if err := checkThreshold("skipped configuration", float64(results.ConfigSkips), float64(args.FailedConfigSkips), false); err != nil {
+		return withFailReason(FailReasonConfigSkips, err)
+	}
+	if err := checkThreshold("assertion-failed", float64(results.AssertionFailures), float64(args.FailedAssertionFailures), false); err != nil {
+		return withFailReason(FailReasonAssertionFailures, err)
+	}
+	if err := checkThreshold("error-failed", float64(results.ErrorFailures), float64(args.FailedErrorFailures), false); err != nil {
+		return withFailReason(FailReasonErrorFailures, err)
 	}
 	return nil
 }
@@ -386,12 +1603,28 @@ func validatePercentageThresholds(results Results, args Args) error {
 
 	failureRate := float64(results.Failures) / float64(totalTests) * 100
 	skipRate := float64(results.Skipped) / float64(totalTests) * 100
+	errorRate := float64(results.Errors) / float64(totalTests) * 100
+	configSkipRate := float64(results.ConfigSkips) / float64(totalTests) * 100
+	assertionFailureRate := float64(results.AssertionFailures) / float64(totalTests) * 100
+	errorFailureRate := float64(results.ErrorFailures) / float64(totalTests) * 100
 
 	if err := checkThreshold("failure", failureRate, float64(args.FailedFails), true); err != nil {
-		return err
+		return withFailReason(FailReasonPercentageFailures, err)
 	}
 	if err := checkThreshold("skip", skipRate, float64(args.FailedSkips), true); err != nil {
-		return err
+		return withFailReason(FailReasonSkips, err)
+	}
+	if err := checkThreshold("error", errorRate, float64(args.FailedErrors), true); err != nil {
+		return withFailReason(FailReasonErrors, err)
+	}
+	if err := checkThreshold("configuration skip", configSkipRate, float64(args.FailedConfigSkips), true); err != nil {
+		return withFailReason(FailReasonConfigSkips, err)
+	}
+	if err := checkThreshold("assertion failure", assertionFailureRate, float64(args.FailedAssertionFailures), true); err != nil {
+		return withFailReason(FailReasonAssertionFailures, err)
+	}
+	if err := checkThreshold("error failure", errorFailureRate, float64(args.FailedErrorFailures), true); err != nil {
+		return withFailReason(FailReasonErrorFailures, err)
 	}
 	return nil
 }
@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
@@ -75,7 +77,7 @@ func TestLocateFiles(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := locateFiles(tc.pattern)
+			result, err := locateFiles(tc.pattern, Args{})
 
 			// Sort results for consistency
 			sort.Strings(result)
@@ -98,7 +100,84 @@ func TestLocateFiles(t *testing.T) {
 	}
 }
 
+func TestLocateFilesSkipsDirectories(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.xml")
+	if err := os.WriteFile(report, []byte("<testng-results/>"), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested.xml"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	result, err := locateFiles(filepath.Join(dir, "*.xml"), Args{})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{report}, result); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // TestProcessFile tests the processFile function with various cases
+func TestLocateFilesDirectoryOnlyPattern(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	_, err := locateFiles(dir+"/", Args{})
+	expected := "pattern matched only directories; did you mean " + filepath.Join(dir, "*.xml") + "?"
+	if err == nil || err.Error() != expected {
+		t.Errorf("locateFiles() expected error %q, got %v", expected, err)
+	}
+}
+
+func TestLocateFilesExpandsEnv(t *testing.T) {
+	t.Setenv("TESTNG_REPORT_DIR", "../testdata")
+
+	result, err := locateFiles("${TESTNG_REPORT_DIR}/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"../testdata/testng-report.xml"}, result); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+	if got := expandPattern("$TESTNG_UNDEFINED_DIR/*.xml"); got != "/*.xml" {
+		t.Errorf("expandPattern() expected %q, got %q", "/*.xml", got)
+	}
+	if len(hook.Entries) != 1 || hook.Entries[0].Level != logrus.WarnLevel {
+		t.Errorf("Expected a warning for the undefined variable, got %+v", hook.Entries)
+	}
+}
+
+func TestLocateFilesWaitsForReports(t *testing.T) {
+	reportPollInterval = 10 * time.Millisecond
+	defer func() { reportPollInterval = 500 * time.Millisecond }()
+
+	dir := t.TempDir()
+	report := filepath.Join(dir, "testng-results.xml")
+	time.AfterFunc(50*time.Millisecond, func() {
+		os.WriteFile(report, []byte("<testng-results/>"), 0644)
+	})
+
+	result, err := locateFiles(filepath.Join(dir, "*.xml"), Args{WaitForReports: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{report}, result); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without matches the wait ends with the usual error
+	if _, err := locateFiles(filepath.Join(dir, "*.json"), Args{WaitForReports: 30 * time.Millisecond}); err == nil {
+		t.Errorf("locateFiles() expected error when no file appears")
+	}
+}
+
 func TestProcessFile(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -111,10 +190,32 @@ func TestProcessFile(t *testing.T) {
 			name:     "ValidTestNGReport",
 			filePath: "../testdata/testng-report.xml",
 			expected: Results{
-				Total:      3,
-				Failures:   1,
-				Skipped:    0,
-				DurationMS: 15.0,
+				Total:             3,
+				Failures:          1,
+				Skipped:           0,
+				DurationMS:        15.0,
+				AssertionFailures: 1,
+			},
+			expectErr: false,
+		},
+		{
+			name:     "ReportWithErrors",
+			filePath: "../testdata/errors/testng-errors.xml",
+			expected: Results{
+				Total:      2,
+				Errors:     2,
+				DurationMS: 10.0,
+			},
+			expectErr: false,
+		},
+		{
+			name:     "ReportWithConfigSkips",
+			filePath: "../testdata/config/testng-config-skips.xml",
+			expected: Results{
+				Total:       4,
+				Skipped:     3,
+				DurationMS:  5.0,
+				ConfigSkips: 2,
 			},
 			expectErr: false,
 		},
@@ -143,7 +244,7 @@ func TestProcessFile(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := processFile(tc.filePath)
+			result, err := processFile(tc.filePath, Args{})
 
 			// Compare results
 			if diff := cmp.Diff(tc.expected, result); diff != "" {
@@ -162,6 +263,113 @@ func TestProcessFile(t *testing.T) {
 	}
 }
 
+// TestProcessFileWithTestDurations tests duration reporting from <test> elements
+func TestProcessFileWithRootElement(t *testing.T) {
+	expected := Results{Total: 3, Failures: 1, DurationMS: 15, AssertionFailures: 1}
+
+	for _, validateSchema := range []bool{false, true} {
+		args := Args{RootElement: "ngtest-results", ValidateSchema: validateSchema}
+		result, err := processFile("../testdata/root/ngtest-results.xml", args)
+		if err != nil {
+			t.Fatalf("processFile() unexpected error (ValidateSchema=%t): %v", validateSchema, err)
+		}
+		if diff := cmp.Diff(expected, result); diff != "" {
+			t.Errorf("processFile() mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// The default root element does not match the renamed root
+	_, err := processFile("../testdata/root/ngtest-results.xml", Args{})
+	if err == nil || !strings.Contains(err.Error(), "no <testng-results> element found") {
+		t.Errorf("processFile() expected a missing root element error, got %v", err)
+	}
+}
+
+func TestProcessFileEmptyReport(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "testng-results.xml")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatalf("Failed to create empty report: %v", err)
+	}
+
+	_, err := processFile(empty, Args{})
+	if err == nil || err.Error() != "empty report file: "+empty {
+		t.Errorf("processFile() expected an empty report file error, got %v", err)
+	}
+
+	agg := processFiles([]string{empty, "../testdata/testng-report.xml"}, Args{})
+	if diff := cmp.Diff([]string{empty}, agg.SkippedFiles); diff != "" {
+		t.Errorf("Skipped files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProcessFileWithInvalidErrors(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	// The suite's errors="n/a" is ignored in favour of its <test> elements
+	results, err := processFile("../testdata/errors/testng-invalid-errors.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.Errors != 1 {
+		t.Errorf("Expected 1 error, got %d", results.Errors)
+	}
+
+	warned := false
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel && entry.Message == "Suite 'ErrorSuite': ignoring invalid errors count 'n/a'" {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("Expected a warning for the invalid errors count")
+	}
+}
+
+func TestProcessFileWithTestDurations(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     Args
+		expected Results
+	}{
+		{
+			name:     "MethodDurations",
+			args:     Args{},
+			expected: Results{Total: 2, DurationMS: 30},
+		},
+		{
+			name:     "SuiteDurations",
+			args:     Args{UseSuiteDuration: true},
+			expected: Results{Total: 2, DurationMS: 350},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := processFile("../testdata/durations/testng-test-durations.xml", tc.args)
+			if err != nil {
+				t.Fatalf("processFile() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, result); diff != "" {
+				t.Errorf("processFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	report, err := parseReport("../testdata/durations/testng-test-durations.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	suite := report.Suites[0]
+	if len(suite.Tests) != 2 || suite.Tests[0].Name != "fast" || suite.Tests[1].DurationMS != "250" {
+		t.Errorf("Unexpected <test> elements: %+v", suite.Tests)
+	}
+	if len(suite.Classes) != 2 {
+		t.Errorf("Expected classes from both <test> elements, got %d", len(suite.Classes))
+	}
+}
+
 // TestValidateInputs tests the ValidateInputs function with various cases
 func TestValidateInputs(t *testing.T) {
 	tests := []struct {
@@ -199,6 +407,34 @@ func TestValidateInputs(t *testing.T) {
 			expectErr: true,
 			errMsg:    "invalid ThresholdMode",
 		},
+		{
+			name: "InvalidNumberFormat",
+			args: Args{
+				ReportFilenamePattern: "testdata/*.xml",
+				NumberFormat:          "dotted",
+			},
+			expectErr: true,
+			errMsg:    "invalid NumberFormat",
+		},
+		{
+			name: "PercentageThresholdOutOfRange",
+			args: Args{
+				ReportFilenamePattern: "testdata/*.xml",
+				FailedFails:           150,
+				ThresholdMode:         "percentage",
+			},
+			expectErr: true,
+			errMsg:    "FailedFails (150) must be between 0 and 100 in percentage mode",
+		},
+		{
+			name: "AbsoluteThresholdAbove100",
+			args: Args{
+				ReportFilenamePattern: "testdata/*.xml",
+				FailedFails:           150,
+				ThresholdMode:         "absolute",
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -217,6 +453,32 @@ func TestValidateInputs(t *testing.T) {
 	}
 }
 
+func TestWarnPercentageLikeThresholds(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{FailedFails: 20, FailedSkips: 5, ThresholdMode: ThresholdModeAbsolute}
+	if err := validateThresholds(Results{Total: 50000, Failures: 3}, args); err != nil {
+		t.Fatalf("validateThresholds() unexpected error: %v", err)
+	}
+	if err := validateThresholds(Results{Total: 500, Failures: 3}, args); err != nil {
+		t.Fatalf("validateThresholds() unexpected error: %v", err)
+	}
+
+	var warnings []string
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	// Only the large run warns, and only about the round threshold
+	expected := []string{"FailedFails (20) is an absolute number of tests, 0.04% of the 50000 tests. Set ThresholdMode to percentage if it was meant as a rate"}
+	if diff := cmp.Diff(expected, warnings); diff != "" {
+		t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // TestValidateThresholds tests the validateThresholds function for various scenarios
 func TestValidateThresholds(t *testing.T) {
 	tests := []struct {
@@ -284,6 +546,95 @@ func TestValidateThresholds(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "ExceededAbsoluteErrorThreshold",
+			results: Results{
+				Total:  10,
+				Errors: 2,
+			},
+			args: Args{
+				FailedErrors:  1,
+				ThresholdMode: "absolute",
+			},
+			expectErr: true,
+			errMsg:    "\nabsolute threshold validation failed: number of errored tests (2) exceeded the threshold (1)",
+		},
+		{
+			name: "ValidAbsoluteErrorThreshold",
+			results: Results{
+				Total:  10,
+				Errors: 2,
+			},
+			args: Args{
+				FailedErrors:  2,
+				ThresholdMode: "absolute",
+			},
+			expectErr: false,
+		},
+		{
+			name: "ExceededPercentageErrorThreshold",
+			results: Results{
+				Total:  10,
+				Errors: 2,
+			},
+			args: Args{
+				FailedErrors:  10,
+				ThresholdMode: "percentage",
+			},
+			expectErr: true,
+			errMsg:    "\npercentage threshold validation failed: error rate (20.00%) exceeded the threshold (10.00%)",
+		},
+		{
+			name: "ExceededAbsoluteConfigSkipThreshold",
+			results: Results{
+				Total:       10,
+				Skipped:     3,
+				ConfigSkips: 2,
+			},
+			args: Args{
+				FailedSkips:       5,
+				FailedConfigSkips: 1,
+				ThresholdMode:     "absolute",
+			},
+			expectErr: true,
+			errMsg:    "\nabsolute threshold validation failed: number of skipped configuration tests (2) exceeded the threshold (1)",
+		},
+		{
+			name: "ZeroDurationWithTests",
+			results: Results{
+				Total:      5,
+				DurationMS: 0,
+			},
+			args: Args{
+				ThresholdMode:      "absolute",
+				FailOnZeroDuration: true,
+			},
+			expectErr: true,
+			errMsg:    "5 tests reported a total duration of 0 ms",
+		},
+		{
+			name: "ZeroDurationWithoutTests",
+			results: Results{
+				Total:      0,
+				DurationMS: 0,
+			},
+			args: Args{
+				ThresholdMode:      "absolute",
+				FailOnZeroDuration: true,
+			},
+			expectErr: false,
+		},
+		{
+			name: "ZeroDurationCheckDisabled",
+			results: Results{
+				Total:      5,
+				DurationMS: 0,
+			},
+			args: Args{
+				ThresholdMode: "absolute",
+			},
+			expectErr: false,
+		},
 		{
 			name: "EdgeCaseVeryHighValues",
 			results: Results{
@@ -330,6 +681,34 @@ func TestValidateThresholds(t *testing.T) {
 	}
 }
 
+func TestExecReportOnly(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "summary.json")
+	args := Args{
+		ReportFilenamePattern:     "../testdata/testng-report.xml",
+		ThresholdMode:             ThresholdModeAbsolute,
+		FailedFails:               0,
+		FailureOnFailedTestConfig: true,
+		ThresholdRules:            `[{"metric": "failures", "mode": "absolute", "limit": 0}]`,
+		MinClasses:                100,
+		MinHealthScore:            99,
+		ExpectedCounts:            "Suite1:10",
+		OutputFile:                output,
+		ReportOnly:                true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() unexpected error with ReportOnly: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("Expected outputs to be written with ReportOnly: %v", err)
+	}
+
+	args.ReportOnly = false
+	if err := Exec(context.Background(), args); err == nil {
+		t.Errorf("Exec() expected an error without ReportOnly")
+	}
+}
+
 func TestExecWithMixedFiles(t *testing.T) {
 	args := Args{
 		ReportFilenamePattern: "../testdata/*.xml",
@@ -385,7 +764,7 @@ func TestProcessFileWithLargeFile(t *testing.T) {
 	tmpFile.Close()
 
 	// Process the large file
-	results, err := processFile(tmpFile.Name())
+	results, err := processFile(tmpFile.Name(), Args{})
 	if err != nil {
 		t.Errorf("processFile() failed for large file: %v", err)
 	} else {
@@ -462,7 +841,7 @@ func TestLogSuiteTestDetailsWithMockLogger(t *testing.T) {
 	}
 
 	// Call the function that generates logs
-	logSuiteTestDetails(suite)
+	logSuiteTestDetails(suite, Args{})
 
 	// Validate logs
 	expectedEntries := []LogEntry{
@@ -486,13 +865,156 @@ func TestLogSuiteTestDetailsWithMockLogger(t *testing.T) {
 	}
 }
 
+func TestLogSuiteTestDetailsCustomFailStatuses(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	suite := Suite{
+		Name: "TestSuite",
+		Classes: []Class{
+			{Name: "Class1", Tests: []Test{{Name: "Test1", Status: "BROKEN", DurationMS: "10", Exception: "SomeException"}}},
+		},
+	}
+
+	logSuiteTestDetails(suite, Args{FailStatuses: "FAIL,BROKEN"})
+
+	expected := "\n    Exception: SomeException"
+	if len(hook.Entries) != 3 || hook.Entries[2].Message != expected {
+		t.Errorf("Expected the exception of a BROKEN test %q, got %+v", expected, hook.Entries)
+	}
+}
+
+func TestLogSuiteTestDetailsTruncatesExceptions(t *testing.T) {
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	exception := "java.lang.IllegalStateException: boom\n" + strings.Repeat("    at com.test.Deep.frame(Deep.java:1)\n", 200)
+	suite := Suite{
+		Name: "TestSuite",
+		Classes: []Class{
+			{Name: "Class1", Tests: []Test{{Name: "Test1", Status: "FAIL", DurationMS: "10", Exception: exception}}},
+		},
+	}
+
+	logSuiteTestDetails(suite, Args{MaxExceptionLength: 100})
+
+	expected := "\n    Exception: " + exception[:100] + "...(truncated)"
+	if len(hook.Entries) != 3 || hook.Entries[2].Message != expected {
+		t.Errorf("Expected a truncated exception %q, got %+v", expected, hook.Entries)
+	}
+
+	if got := truncateText("short", 100); got != "short" {
+		t.Errorf("truncateText() expected short text unchanged, got %q", got)
+	}
+	if got := truncateText(exception, 0); got != exception {
+		t.Errorf("truncateText() expected no truncation without a limit")
+	}
+}
+
+func TestLogSuiteTestDetailsTrimsExceptions(t *testing.T) {
+	exception := "\n\t  java.lang.AssertionError:   expected [1]\r\n   but found [2]\n\n\tat com.test.Class1.test1(Class1.java:12)  \n"
+	suite := Suite{
+		Name: "TestSuite",
+		Classes: []Class{
+			{Name: "Class1", Tests: []Test{{Name: "Test1", Status: "FAIL", DurationMS: "10", Exception: exception}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected string
+	}{
+		{
+			name:     "Trimmed",
+			args:     Args{TrimExceptions: true},
+			expected: "java.lang.AssertionError: expected [1] but found [2] at com.test.Class1.test1(Class1.java:12)",
+		},
+		{
+			name:     "TrimmedBeforeTruncation",
+			args:     Args{TrimExceptions: true, MaxExceptionLength: 24},
+			expected: "java.lang.AssertionError...(truncated)",
+		},
+		{
+			name:     "Untouched",
+			args:     Args{},
+			expected: exception,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			logSuiteTestDetails(suite, tc.args)
+
+			expected := "\n    Exception: " + tc.expected
+			if len(hook.Entries) != 3 || hook.Entries[2].Message != expected {
+				t.Errorf("Expected exception log %q, got %+v", expected, hook.Entries)
+			}
+		})
+	}
+
+	// The parsed exception is left intact for outputs
+	if suite.Classes[0].Tests[0].Exception != exception {
+		t.Errorf("Expected the parsed exception to be unchanged")
+	}
+}
+
+func TestLogSuiteTestDetailsSectioned(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	suite := Suite{
+		Name: "TestSuite",
+		Classes: []Class{
+			{
+				Name: "Class1",
+				Tests: []Test{
+					{Name: "Test1", Status: "PASS", DurationMS: "10"},
+					{Name: "Test2", Status: "SKIP", DurationMS: "0"},
+					{Name: "Test3", Status: "FAIL", DurationMS: "20", Exception: "SomeException"},
+				},
+			},
+			{
+				Name:  "Class2",
+				Tests: []Test{{Name: "Test4", Status: "FAIL", DurationMS: "5"}},
+			},
+		},
+	}
+
+	logSuiteTestDetails(suite, Args{SectionedOutput: true})
+
+	expected := []string{
+		"\nTest Details:",
+		"\nFailures (2):",
+		"\n- Test: Test3 | Status: FAIL | Duration: 20 ms",
+		"\n    Exception: SomeException",
+		"\n- Test: Test4 | Status: FAIL | Duration: 5 ms",
+		"\nSkips (1):",
+		"\n- Test: Test2 | Status: SKIP | Duration: 0 ms",
+		"\nPasses (1):",
+		"\n- Test: Test1 | Status: PASS | Duration: 10 ms",
+	}
+	var got []string
+	for _, entry := range hook.Entries {
+		got = append(got, entry.Message)
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Log messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestLogSuiteSummaryWithMockLogger(t *testing.T) {
 	// Setup mock log hook
 	hook := NewMockLogHook()
 	logrus.AddHook(hook)
 
-	// Input suite name and results to log
-	suiteName := "SampleSuite"
+	// Input suite and results to log
+	suite := Suite{Name: "SampleSuite"}
 	results := Results{
 		Total:      10,
 		Failures:   2,
@@ -501,13 +1023,14 @@ func TestLogSuiteSummaryWithMockLogger(t *testing.T) {
 	}
 
 	// Call the function that generates logs
-	logSuiteSummary(suiteName, results)
+	logSuiteSummary(suite, results, Args{})
 
 	// Validate logs
 	expectedEntries := []LogEntry{
 		{Message: "\n==============================================="},
 		{Message: "\nSuite: SampleSuite"},
 		{Message: "\nTotal Tests: 10 | Failures: 2 | Skips: 1 | Duration: 100.00 ms"},
+		{Message: "\nParallel: unset | Thread Count: unset"},
 		{Message: "\n==============================================="},
 	}
 
@@ -523,6 +1046,135 @@ func TestLogSuiteSummaryWithMockLogger(t *testing.T) {
 	}
 }
 
+func TestFormatResultsNumberFormat(t *testing.T) {
+	results := Results{Total: 1000000, Failures: 12345, Skipped: 999, Errors: 1000, DurationMS: 1500.5}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "Default",
+			format:   "",
+			expected: "1000000 | Failures: 12345 | Errors: 1000 | Skips: 999 | Duration: 1500.50 ms",
+		},
+		{
+			name:     "Plain",
+			format:   NumberFormatPlain,
+			expected: "1000000 | Failures: 12345 | Errors: 1000 | Skips: 999 | Duration: 1500.50 ms",
+		},
+		{
+			name:     "Grouped",
+			format:   NumberFormatGrouped,
+			expected: "1,000,000 | Failures: 12,345 | Errors: 1,000 | Skips: 999 | Duration: 1500.50 ms",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatResults(results, Args{NumberFormat: tc.format}); got != tc.expected {
+				t.Errorf("formatResults() expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatTestNames(t *testing.T) {
+	names := []string{"test1", "test2", "test3", "test4", "test5"}
+
+	tests := []struct {
+		name     string
+		maxNames int
+		expected string
+	}{
+		{name: "Unlimited", maxNames: 0, expected: "test1, test2, test3, test4, test5"},
+		{name: "WithinLimit", maxNames: 5, expected: "test1, test2, test3, test4, test5"},
+		{name: "Truncated", maxNames: 2, expected: "test1, test2 ... and 3 more"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatTestNames(names, tc.maxNames); got != tc.expected {
+				t.Errorf("formatTestNames() expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatResultsHideZeroMetrics(t *testing.T) {
+	results := Results{Total: 10, Failures: 2, DurationMS: 100}
+
+	expected := "10 | Failures: 2 | Skips: 0 | Duration: 100.00 ms"
+	if got := formatResults(results, Args{}); got != expected {
+		t.Errorf("formatResults() expected %q, got %q", expected, got)
+	}
+
+	expected = "10 | Failures: 2 | Duration: 100.00 ms"
+	if got := formatResults(results, Args{HideZeroMetrics: true}); got != expected {
+		t.Errorf("formatResults() with HideZeroMetrics expected %q, got %q", expected, got)
+	}
+}
+
+func TestFullySkippedClasses(t *testing.T) {
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	results, err := processFile("../testdata/skips/testng-skipped-classes.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.FullySkippedClasses != 1 || results.Skipped != 3 {
+		t.Errorf("Expected 1 fully skipped class and 3 skipped tests, got %+v", results)
+	}
+
+	var skippedClasses []string
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "Entirely skipped class:") {
+			skippedClasses = append(skippedClasses, entry.Message)
+		}
+	}
+	if diff := cmp.Diff([]string{"\nEntirely skipped class: com.test.Disabled"}, skippedClasses); diff != "" {
+		t.Errorf("Skipped class log mismatch (-want +got):\n%s", diff)
+	}
+
+	args := Args{ThresholdMode: ThresholdModeAbsolute, MaxFullySkippedClasses: 1}
+	if err := validateThresholds(results, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+	results.FullySkippedClasses = 2
+	if err := validateThresholds(results, args); err == nil || !strings.Contains(err.Error(), "2 classes were entirely skipped") {
+		t.Errorf("validateThresholds() expected fully skipped classes error, got %v", err)
+	}
+}
+
+func TestLogSuiteSummaryWithParallelSettings(t *testing.T) {
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	report, err := parseReport("../testdata/parallel/testng-parallel.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+	suite := report.Suites[0]
+	if suite.Parallel != "methods" || suite.ThreadCount != "4" {
+		t.Errorf("Expected parallel=methods and thread-count=4, got %q and %q", suite.Parallel, suite.ThreadCount)
+	}
+
+	hook.Entries = nil
+	logSuiteSummary(suite, Results{}, Args{})
+
+	found := false
+	for _, entry := range hook.Entries {
+		if entry.Message == "\nParallel: methods | Thread Count: 4" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the parallel settings in the suite summary, got %+v", hook.Entries)
+	}
+}
+
 // TestAggregateClassResultsWithInvalidDuration tests handling of invalid DurationMS and failed/skipped tests.
 func TestAggregateClassResultsWithInvalidDuration(t *testing.T) {
 	// Test data: Class with valid and invalid DurationMS
@@ -541,14 +1193,16 @@ func TestAggregateClassResultsWithInvalidDuration(t *testing.T) {
 	logrus.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: false})
 
 	// Call the function to aggregate class results
-	results, failedTests, skippedTests := aggregateClassResults(class)
+	results, failedTests, skippedTests := aggregateClassResults(class, Args{})
 
 	// Define the expected aggregated results
 	expectedResults := Results{
-		Total:      3,
-		Failures:   1,
-		Skipped:    1,
-		DurationMS: 15,
+		Total:             3,
+		Failures:          1,
+		Skipped:           1,
+		DurationMS:        15,
+		MissingExceptions: 1,
+		InvalidDurations:  1,
 	}
 
 	// Expected failed and skipped test names
@@ -585,6 +1239,75 @@ func TestAggregateClassResultsWithInvalidDuration(t *testing.T) {
 	}
 }
 
+func TestExecWithExpectedCounts(t *testing.T) {
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report-valid.xml",
+		FailedFails:           4,
+		ThresholdMode:         ThresholdModeAbsolute,
+		ExpectedCounts:        "Suite1:3,Suite2:4",
+	}
+
+	if err := Exec(context.Background(), args); err == nil || !strings.Contains(err.Error(), "Suite2 (expected 4, found 3)") {
+		t.Errorf("Exec() expected an under-count error, got %v", err)
+	}
+
+	args.WarnOnly = true
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() with WarnOnly unexpected error: %v", err)
+	}
+}
+
+// TestAggregateClassResultsWithCustomStatuses tests user-defined fail and skip statuses.
+func TestAggregateClassResultsWithCustomStatuses(t *testing.T) {
+	class := Class{
+		Name: "TestClass",
+		Tests: []Test{
+			{Name: "Test1", Status: "PASS", DurationMS: "1"},
+			{Name: "Test2", Status: "FAIL", DurationMS: "1"},
+			{Name: "Test3", Status: "BROKEN", DurationMS: "1"},
+			{Name: "Test4", Status: "SKIP", DurationMS: "1"},
+			{Name: "Test5", Status: "excluded", DurationMS: "1"},
+			{Name: "Test6", Status: "IGNORED", DurationMS: "1"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		args            Args
+		expectedFailed  []string
+		expectedSkipped []string
+	}{
+		{
+			name:            "DefaultStatuses",
+			args:            Args{},
+			expectedFailed:  []string{"Test2"},
+			expectedSkipped: []string{"Test4", "Test6"},
+		},
+		{
+			name:            "CustomStatuses",
+			args:            Args{FailStatuses: "FAIL, BROKEN", SkipStatuses: "SKIP,EXCLUDED"},
+			expectedFailed:  []string{"Test2", "Test3"},
+			expectedSkipped: []string{"Test4", "Test5"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, failedTests, skippedTests := aggregateClassResults(class, tc.args)
+
+			if results.Total != 6 || results.Failures != len(tc.expectedFailed) || results.Skipped != len(tc.expectedSkipped) {
+				t.Errorf("Unexpected results: %+v", results)
+			}
+			if diff := cmp.Diff(tc.expectedFailed, failedTests); diff != "" {
+				t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedSkipped, skippedTests); diff != "" {
+				t.Errorf("Skipped tests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestExecWithMixedValidAndInvalidFiles(t *testing.T) {
 	args := Args{
 		ReportFilenamePattern: "../testdata/*.xml", // Adjust this path as necessary
@@ -618,7 +1341,7 @@ func TestExecWithMixedValidAndInvalidFiles(t *testing.T) {
 	// Start processing files in parallel
 	for _, file := range files {
 		go func(f string) {
-			res, err := processFile(f)
+			res, err := processFile(f, Args{})
 			if err != nil {
 				errorsChan <- fmt.Errorf("failed to process file %s: %w", f, err)
 				return
@@ -669,3 +1392,64 @@ func TestExecWithMixedValidAndInvalidFiles(t *testing.T) {
 		t.Log("Threshold validation passed successfully.")
 	}
 }
+
+func TestParseReportRetriesTransientOpenErrors(t *testing.T) {
+	defer func(open func(string) (*os.File, error), backoff time.Duration) {
+		openFile, openRetryBackoff = open, backoff
+	}(openFile, openRetryBackoff)
+	openRetryBackoff = time.Millisecond
+
+	tests := []struct {
+		name          string
+		openErr       error
+		retries       int
+		expectErr     bool
+		expectedCalls int
+	}{
+		{
+			name:          "TransientErrorRecovers",
+			openErr:       syscall.EBUSY,
+			retries:       3,
+			expectErr:     false,
+			expectedCalls: 3,
+		},
+		{
+			name:          "TransientErrorExhaustsRetries",
+			openErr:       syscall.EAGAIN,
+			retries:       1,
+			expectErr:     true,
+			expectedCalls: 2,
+		},
+		{
+			name:          "PermissionErrorNotRetried",
+			openErr:       os.ErrPermission,
+			retries:       3,
+			expectErr:     true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			// Fail the first two opens, then open the real file
+			openFile = func(name string) (*os.File, error) {
+				calls++
+				if calls <= 2 {
+					return nil, &os.PathError{Op: "open", Path: name, Err: tc.openErr}
+				}
+				return os.Open(name)
+			}
+
+			_, err := parseReport("../testdata/testng-report.xml", Args{OpenRetries: tc.retries})
+			if tc.expectErr && err == nil {
+				t.Errorf("parseReport() expected error")
+			} else if !tc.expectErr && err != nil {
+				t.Errorf("parseReport() unexpected error: %v", err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("Expected %d open attempts, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// postCommandWaitDelay bounds how long the output of a killed post command is
+// drained before giving up.
+const postCommandWaitDelay = time.Second
+
+// runPostCommand runs command with sh, passing the JSON summary on stdin.
+// The command's output is forwarded to the log. A non-zero timeout kills the
+// command when exceeded.
+func runPostCommand(ctx context.Context, command string, summary Summary, timeout time.Duration) error {
+	input, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON summary for post command: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Stop waiting for output held open by orphaned children once killed
+	cmd.WaitDelay = postCommandWaitDelay
+
+	err = cmd.Run()
+	if out := strings.TrimSpace(output.String()); out != "" {
+		logrus.Infof("\nPost command output:\n%s", out)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("post command timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("post command failed: %w", err)
+	}
+	return nil
+}
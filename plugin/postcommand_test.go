@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExecPostCommand(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "summary.json")
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		PostCommand:           "cat > " + marker,
+		Timeout:               10 * time.Second,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("Expected the post command to write %s: %v", marker, err)
+	}
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Failed to decode the summary passed on stdin: %v", err)
+	}
+	if diff := cmp.Diff(Summary{Total: 3, Failures: 1, DurationMS: 15}, summary); diff != "" {
+		t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecPostCommandFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     string
+		failOn      bool
+		expectError bool
+	}{
+		{name: "Warn", command: "exit 3", failOn: false, expectError: false},
+		{name: "Fail", command: "exit 3", failOn: true, expectError: true},
+		{name: "Timeout", command: "sleep 5", failOn: true, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModeAbsolute,
+				PostCommand:           tc.command,
+				FailOnPostCommand:     tc.failOn,
+				Timeout:               100 * time.Millisecond,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Exec() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// promMetric describes a single gauge written to the Prometheus textfile.
+type promMetric struct {
+	Name  string
+	Help  string
+	Value float64
+}
+
+// formatPromMetrics renders the results in the Prometheus text exposition format.
+// When label is set, every sample carries it as a label.
+func formatPromMetrics(results Results, label string) string {
+	metrics := []promMetric{
+		{Name: "testng_tests_total", Help: "Total number of TestNG tests executed.", Value: float64(results.Total)},
+		{Name: "testng_failures_total", Help: "Number of failed TestNG tests.", Value: float64(results.Failures)},
+		{Name: "testng_skipped_total", Help: "Number of skipped TestNG tests.", Value: float64(results.Skipped)},
+		{Name: "testng_duration_ms", Help: "Total duration of TestNG tests in milliseconds.", Value: results.DurationMS},
+	}
+
+	labels := ""
+	if label != "" {
+		labels = fmt.Sprintf(`{label="%s"}`, escapePromLabel(label))
+	}
+
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n", m.Name, m.Help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", m.Name)
+		fmt.Fprintf(&b, "%s%s %s\n", m.Name, labels, strconv.FormatFloat(m.Value, 'f', -1, 64))
+	}
+	return b.String()
+}
+
+// escapePromLabel escapes a label value per the exposition format.
+func escapePromLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// writePromFile writes the metrics to filename. The file is written to a
+// temporary path first and renamed so collectors never read a partial file.
+func writePromFile(filename string, results Results, label string) error {
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, []byte(formatPromMetrics(results, label)), 0644); err != nil {
+		return fmt.Errorf("failed to write Prometheus metrics to %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return fmt.Errorf("failed to move Prometheus metrics to %s: %w", filename, err)
+	}
+	return nil
+}
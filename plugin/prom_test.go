@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWritePromFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		label    string
+		expected string
+	}{
+		{
+			name:  "WithLabel",
+			label: `unit "fast"`,
+			expected: `# HELP testng_tests_total Total number of TestNG tests executed.
+# TYPE testng_tests_total gauge
+testng_tests_total{label="unit \"fast\""} 10
+# HELP testng_failures_total Number of failed TestNG tests.
+# TYPE testng_failures_total gauge
+testng_failures_total{label="unit \"fast\""} 2
+# HELP testng_skipped_total Number of skipped TestNG tests.
+# TYPE testng_skipped_total gauge
+testng_skipped_total{label="unit \"fast\""} 1
+# HELP testng_duration_ms Total duration of TestNG tests in milliseconds.
+# TYPE testng_duration_ms gauge
+testng_duration_ms{label="unit \"fast\""} 123.5
+`,
+		},
+		{
+			name: "WithoutLabel",
+			expected: `# HELP testng_tests_total Total number of TestNG tests executed.
+# TYPE testng_tests_total gauge
+testng_tests_total 10
+# HELP testng_failures_total Number of failed TestNG tests.
+# TYPE testng_failures_total gauge
+testng_failures_total 2
+# HELP testng_skipped_total Number of skipped TestNG tests.
+# TYPE testng_skipped_total gauge
+testng_skipped_total 1
+# HELP testng_duration_ms Total duration of TestNG tests in milliseconds.
+# TYPE testng_duration_ms gauge
+testng_duration_ms 123.5
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "testng.prom")
+			results := Results{Total: 10, Failures: 2, Skipped: 1, DurationMS: 123.5}
+			if err := writePromFile(output, results, tc.label); err != nil {
+				t.Fatalf("writePromFile() unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("Failed to read metrics file: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, string(data)); diff != "" {
+				t.Errorf("Metrics mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
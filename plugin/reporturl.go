@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// redactedURL returns rawURL with any password in its user info masked, so it
+// can be logged and included in errors.
+func redactedURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+// fetchReport downloads the report at rawURL into a temporary file and returns
+// its path. The caller removes the file once it has been processed. A
+// ReportURLToken is sent as a Bearer token, otherwise ReportURLUser and
+// ReportURLPass are sent as basic auth. A non-zero timeout bounds the request.
+func fetchReport(ctx context.Context, rawURL string, args Args, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	display := redactedURL(rawURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for report %s: %w", display, err)
+	}
+	switch {
+	case args.ReportURLToken != "":
+		req.Header.Set("Authorization", "Bearer "+args.ReportURLToken)
+	case args.ReportURLUser != "":
+		req.SetBasicAuth(args.ReportURLUser, args.ReportURLPass)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The client error embeds the URL, so report the redacted one instead
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("failed to fetch report %s: %w", display, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("failed to fetch report %s: %s: %s", display, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	f, err := os.CreateTemp("", "testng-report-*.xml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file for report %s: %w", display, err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download report %s: %w", display, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download report %s: %w", display, err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Metrics supported by threshold rules
+const (
+	MetricFailures = "failures"
+	MetricSkipped  = "skipped"
+	MetricErrors   = "errors"
+)
+
+// ThresholdRule is a single threshold evaluated in addition to the primary
+// threshold mode, e.g. {"metric": "skipped", "mode": "percentage", "limit": 2}.
+type ThresholdRule struct {
+	Metric string  `json:"metric"`
+	Mode   string  `json:"mode"`
+	Limit  float64 `json:"limit"`
+}
+
+// parseThresholdRules parses and validates a JSON array of threshold rules.
+func parseThresholdRules(rules string) ([]ThresholdRule, error) {
+	if strings.TrimSpace(rules) == "" {
+		return nil, nil
+	}
+
+	var parsed []ThresholdRule
+	if err := json.Unmarshal([]byte(rules), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid ThresholdRules value. It must be a JSON array of {metric, mode, limit} objects: %v", err)
+	}
+
+	for i, rule := range parsed {
+		switch rule.Metric {
+		case MetricFailures, MetricSkipped, MetricErrors:
+		default:
+			return nil, fmt.Errorf("invalid metric '%s' in threshold rule %d. It must be 'failures', 'skipped' or 'errors'", rule.Metric, i+1)
+		}
+		if rule.Mode != ThresholdModeAbsolute && rule.Mode != ThresholdModePercentage {
+			return nil, fmt.Errorf("invalid mode '%s' in threshold rule %d. It must be 'absolute' or 'percentage'", rule.Mode, i+1)
+		}
+		if rule.Limit < 0 {
+			return nil, fmt.Errorf("invalid limit in threshold rule %d. It must be non-negative", i+1)
+		}
+	}
+	return parsed, nil
+}
+
+// evaluate returns an error when the results breach the rule.
+func (r ThresholdRule) evaluate(results Results) error {
+	var count int
+	switch r.Metric {
+	case MetricFailures:
+		count = results.Failures
+	case MetricSkipped:
+		count = results.Skipped
+	case MetricErrors:
+		count = results.Errors
+	}
+
+	if r.Mode == ThresholdModePercentage {
+		if results.Total == 0 {
+			return nil
+		}
+		rate := float64(count) / float64(results.Total) * 100
+		if rate > r.Limit {
+			return fmt.Errorf("threshold rule breached: %s rate (%.2f%%) exceeded the limit (%.2f%%)", r.Metric, rate, r.Limit)
+		}
+		return nil
+	}
+
+	if float64(count) > r.Limit {
+		return fmt.Errorf("threshold rule breached: number of %s (%d) exceeded the limit (%s)", r.Metric, count, formatLimit(r.Limit))
+	}
+	return nil
+}
+
+// formatLimit formats an absolute limit without trailing zeros.
+func formatLimit(limit float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", limit), "0"), ".")
+}
+
+// validateThresholdRules evaluates every rule and returns one error per breached rule.
+func validateThresholdRules(results Results, rules string) error {
+	parsed, err := parseThresholdRules(rules)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rule := range parsed {
+		if err := rule.evaluate(results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseThresholdRules(t *testing.T) {
+	rules, err := parseThresholdRules(`[{"metric": "failures", "mode": "absolute", "limit": 5}, {"metric": "skipped", "mode": "percentage", "limit": 2}]`)
+	if err != nil {
+		t.Fatalf("parseThresholdRules() unexpected error: %v", err)
+	}
+	if len(rules) != 2 || rules[1].Metric != MetricSkipped || rules[1].Limit != 2 {
+		t.Errorf("parseThresholdRules() unexpected rules: %+v", rules)
+	}
+
+	invalid := []string{
+		`{"metric": "failures"}`,
+		`[{"metric": "passes", "mode": "absolute", "limit": 1}]`,
+		`[{"metric": "failures", "mode": "relative", "limit": 1}]`,
+		`[{"metric": "failures", "mode": "absolute", "limit": -1}]`,
+	}
+	for _, rules := range invalid {
+		if _, err := parseThresholdRules(rules); err == nil {
+			t.Errorf("parseThresholdRules(%s) expected error", rules)
+		}
+	}
+}
+
+func TestValidateThresholdsWithRules(t *testing.T) {
+	rules := `[{"metric": "failures", "mode": "absolute", "limit": 5}, {"metric": "skipped", "mode": "percentage", "limit": 2}]`
+
+	tests := []struct {
+		name      string
+		results   Results
+		expectErr bool
+		errMsgs   []string
+	}{
+		{
+			name:    "NoRuleBreached",
+			results: Results{Total: 100, Failures: 5, Skipped: 2},
+		},
+		{
+			name:      "SkipRateRuleBreached",
+			results:   Results{Total: 100, Failures: 1, Skipped: 3},
+			expectErr: true,
+			errMsgs:   []string{"skipped rate (3.00%) exceeded the limit (2.00%)"},
+		},
+		{
+			name:      "BothRulesBreached",
+			results:   Results{Total: 100, Failures: 6, Skipped: 3},
+			expectErr: true,
+			errMsgs:   []string{"number of failures (6) exceeded the limit (5)", "skipped rate (3.00%) exceeded the limit (2.00%)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// The primary thresholds are disabled so only the rules apply
+			args := Args{ThresholdMode: ThresholdModeAbsolute, ThresholdRules: rules}
+			err := validateThresholds(tc.results, args)
+
+			if !tc.expectErr {
+				if err != nil {
+					t.Errorf("validateThresholds() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validateThresholds() expected error")
+			}
+			for _, msg := range tc.errMsgs {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("validateThresholds() error %q does not contain %q", err, msg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// reportNesting describes the element nesting of TestNG results reports:
+// each element maps to the child elements it may contain. Elements mapped to
+// nil only contain text. It is a structural check only, not the TestNG XSD;
+// attributes and their types are not checked.
+var reportNesting = map[string][]string{
+	"testng-results":   {"reporter-output", "suite"},
+	"reporter-output":  {"line"},
+	"line":             nil,
+	"suite":            {"groups", "test", "class"},
+	"groups":           {"group"},
+	"group":            {"method"},
+	"method":           nil,
+	"test":             {"groups", "class"},
+	"class":            {"test-method"},
+	"test-method":      {"params", "exception", "reporter-output", "attributes", "retry-analyzer"},
+	"params":           {"param"},
+	"param":            {"value"},
+	"value":            nil,
+	"attributes":       {"attribute"},
+	"attribute":        nil,
+	"exception":        {"message", "full-stacktrace", "short-stacktrace"},
+	"message":          nil,
+	"full-stacktrace":  nil,
+	"short-stacktrace": nil,
+	"retry-analyzer":   nil,
+}
+
+// validateNesting checks that the elements of the report read from r are
+// nested as reportNesting allows. Well-formed reports with misplaced elements
+// would otherwise be aggregated from partial data. The root element must be
+// named root and may contain what <testng-results> may. With allowJUnit, a
+// JUnit root is accepted and its elements are not checked, as reportNesting
+// only describes TestNG reports.
+func validateNesting(r io.Reader, root string, allowJUnit bool) error {
+	decoder := xml.NewDecoder(r)
+	var stack []string
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			name := el.Name.Local
+			line, _ := decoder.InputPos()
+			if len(stack) == 0 {
+				if allowJUnit && name != root && isJUnitRoot(name) {
+					return nil
+				}
+				if name != root {
+					return fmt.Errorf("root element <%s> is not <%s> (line %d)", name, root, line)
+				}
+				name = DefaultRootElement
+			} else if parent := stack[len(stack)-1]; !allowedChild(parent, name) {
+				return fmt.Errorf("element <%s> is not allowed inside <%s> (line %d)", name, parent, line)
+			}
+			stack = append(stack, name)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return nil
+}
+
+// allowedChild reports whether reportNesting allows child inside parent.
+func allowedChild(parent, child string) bool {
+	for _, allowed := range reportNesting[parent] {
+		if allowed == child {
+			return true
+		}
+	}
+	return false
+}
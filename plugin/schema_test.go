@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReportValidateNesting(t *testing.T) {
+	tests := []struct {
+		name      string
+		filePath  string
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:     "ValidReport",
+			filePath: "../testdata/testng-report.xml",
+		},
+		{
+			name:     "ValidReportWithTestScopedGroups",
+			filePath: "../testdata/groups/testng-test-groups.xml",
+		},
+		{
+			name:     "ValidReportWithDirectClass",
+			filePath: "../testdata/nesting/testng-mixed-classes.xml",
+		},
+		{
+			name:      "MisplacedTestMethod",
+			filePath:  "../testdata/schema/testng-misplaced-test-method.xml",
+			expectErr: true,
+			errMsg:    "element <test-method> is not allowed inside <suite> (line 11)",
+		},
+		{
+			name:      "UnknownSuiteElement",
+			filePath:  "../testdata/invalid-suite.xml",
+			expectErr: true,
+			errMsg:    "element <suite1> is not allowed inside <testng-results> (line 2)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := parseReport(tc.filePath, Args{ValidateSchema: true})
+			if !tc.expectErr {
+				if err != nil {
+					t.Fatalf("parseReport() unexpected error: %v", err)
+				}
+				if len(report.Suites) == 0 {
+					t.Errorf("parseReport() returned no suites after the nesting check")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("parseReport() expected error containing %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SlackMessage is a Slack message payload that can be posted as-is to an
+// incoming webhook or chat.postMessage.
+type SlackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackEscaper escapes the characters Slack reserves for control sequences.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// renderSlackMessage renders the aggregated results in Slack's mrkdwn format.
+// Unlike Markdown, mrkdwn has no headings or tables, bolds with single
+// asterisks and requires &, < and > to be escaped.
+func renderSlackMessage(agg *aggregate, args Args) string {
+	var b strings.Builder
+
+	res := agg.Results
+	indicator := ":large_green_circle:"
+	if res.Failures > 0 {
+		indicator = ":red_circle:"
+	}
+
+	title := "TestNG Results"
+	if args.Label != "" {
+		title += ": " + slackEscaper.Replace(args.Label)
+	}
+	fmt.Fprintf(&b, "%s *%s*\n", indicator, title)
+	fmt.Fprintf(&b, "*Total:* %d | *Passed:* %d | *Failures:* %d | *Skips:* %d | *Duration:* %.2f ms\n",
+		res.Total, res.Total-res.Failures-res.Skipped, res.Failures, res.Skipped, res.DurationMS)
+
+	failedTests := agg.FailedTests
+	if args.AnonymizeNames {
+		failedTests = anonymizeNames(failedTests)
+	}
+	if len(failedTests) > 0 {
+		b.WriteString("\n*Failed Tests*\n")
+		for _, name := range failedTests {
+			fmt.Fprintf(&b, "• `%s`\n", slackEscaper.Replace(name))
+		}
+		if agg.DroppedNames > 0 {
+			fmt.Fprintf(&b, "_... and %d more_\n", agg.DroppedNames)
+		}
+	}
+
+	return b.String()
+}
+
+// writeSlackMessage writes the aggregated results to filename as a Slack
+// message payload.
+func writeSlackMessage(filename string, agg *aggregate, args Args) error {
+	data, err := json.MarshalIndent(SlackMessage{Text: renderSlackMessage(agg, args)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write Slack message to %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteSlackMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   Results
+		failed    []string
+		anonymize bool
+		expected  string
+	}{
+		{
+			name:    "Failures",
+			results: Results{Total: 3, Failures: 1, Skipped: 1, DurationMS: 15},
+			failed:  []string{"com.test.TestOne.test1", "com.test.List<String>.test2"},
+			expected: ":red_circle: *TestNG Results: unit*\n" +
+				"*Total:* 3 | *Passed:* 1 | *Failures:* 1 | *Skips:* 1 | *Duration:* 15.00 ms\n" +
+				"\n*Failed Tests*\n" +
+				"• `com.test.TestOne.test1`\n" +
+				"• `com.test.List&lt;String&gt;.test2`\n",
+		},
+		{
+			name:      "Anonymized",
+			results:   Results{Total: 3, Failures: 1, DurationMS: 15},
+			failed:    []string{"com.test.TestOne.test1"},
+			anonymize: true,
+			expected: ":red_circle: *TestNG Results: unit*\n" +
+				"*Total:* 3 | *Passed:* 2 | *Failures:* 1 | *Skips:* 0 | *Duration:* 15.00 ms\n" +
+				"\n*Failed Tests*\n" +
+				"• `" + anonymizeName("com.test.TestOne.test1") + "`\n",
+		},
+		{
+			name:    "Passed",
+			results: Results{Total: 2, DurationMS: 10},
+			expected: ":large_green_circle: *TestNG Results: unit*\n" +
+				"*Total:* 2 | *Passed:* 2 | *Failures:* 0 | *Skips:* 0 | *Duration:* 10.00 ms\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			agg := newAggregate(0, false)
+			agg.add(fileReport{Results: tc.results}, tc.failed, nil)
+
+			output := filepath.Join(t.TempDir(), "slack.json")
+			if err := writeSlackMessage(output, agg, Args{Label: "unit", AnonymizeNames: tc.anonymize}); err != nil {
+				t.Fatalf("writeSlackMessage() unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("Failed to read Slack message: %v", err)
+			}
+
+			var message SlackMessage
+			if err := json.Unmarshal(data, &message); err != nil {
+				t.Fatalf("Failed to decode Slack message: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, message.Text); diff != "" {
+				t.Errorf("Slack message mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	// Pure-Go SQLite driver, so the plugin builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+const createRunsTable = `CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp   TEXT NOT NULL,
+	label       TEXT NOT NULL,
+	total       INTEGER NOT NULL,
+	failures    INTEGER NOT NULL,
+	skipped     INTEGER NOT NULL,
+	duration_ms REAL NOT NULL
+)`
+
+// writeSQLite creates or opens the SQLite database at filename and appends
+// the run record to its runs table.
+func writeSQLite(filename string, record RunRecord) error {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database %s: %w", filename, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(createRunsTable); err != nil {
+		return fmt.Errorf("failed to create runs table in %s: %w", filename, err)
+	}
+
+	_, err = db.Exec(
+		`INSERT INTO runs (timestamp, label, total, failures, skipped, duration_ms) VALUES (?, ?, ?, ?, ?, ?)`,
+		record.Timestamp.UTC().Format(time.RFC3339), record.Label, record.Total, record.Failures, record.Skipped, record.DurationMS,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert run into %s: %w", filename, err)
+	}
+	return nil
+}
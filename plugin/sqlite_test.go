@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteSQLite(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "history.db")
+	timestamp := time.Date(2024, 5, 28, 12, 14, 37, 0, time.UTC)
+	record := newRunRecord(Results{Total: 10, Failures: 2, Skipped: 1, DurationMS: 123.5}, "unit", timestamp)
+
+	// Insert twice to confirm an existing database is appended to
+	for i := 0; i < 2; i++ {
+		if err := writeSQLite(dbFile, record); err != nil {
+			t.Fatalf("writeSQLite() unexpected error: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT timestamp, label, total, failures, skipped, duration_ms FROM runs ORDER BY id`)
+	if err != nil {
+		t.Fatalf("Failed to query runs: %v", err)
+	}
+	defer rows.Close()
+
+	var got []RunRecord
+	for rows.Next() {
+		var r RunRecord
+		var ts string
+		if err := rows.Scan(&ts, &r.Label, &r.Total, &r.Failures, &r.Skipped, &r.DurationMS); err != nil {
+			t.Fatalf("Failed to scan run: %v", err)
+		}
+		if r.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+			t.Fatalf("Failed to parse timestamp %q: %v", ts, err)
+		}
+		got = append(got, r)
+	}
+
+	if diff := cmp.Diff([]RunRecord{record, record}, got); diff != "" {
+		t.Errorf("Runs mismatch (-want +got):\n%s", diff)
+	}
+}
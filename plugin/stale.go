@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buildStartTime reads the build start time from the environment variable
+// name, as Unix seconds like DRONE_BUILD_STARTED or as an RFC 3339 time. It
+// reports false when the variable is unset or invalid.
+func buildStartTime(name string) (time.Time, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if name == "" || value == "" {
+		return time.Time{}, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	warnf("Ignoring build start time '%s' in %s; expected Unix seconds or an RFC 3339 time", value, name)
+	return time.Time{}, false
+}
+
+// checkStaleReport returns an error when the report file was last modified
+// before the build started, which means it was left over from an earlier
+// run. Unless FailOnStaleReport is set the report is only warned about.
+func checkStaleReport(file *os.File, args Args) error {
+	start, ok := buildStartTime(args.BuildStartEnv)
+	if !ok {
+		return nil
+	}
+
+	info, err := file.Stat()
+	if err != nil || !info.ModTime().Before(start) {
+		return nil
+	}
+
+	modified := info.ModTime().UTC().Format(time.RFC3339)
+	started := start.UTC().Format(time.RFC3339)
+	if args.FailOnStaleReport {
+		return fmt.Errorf("stale report file: %s was last modified at %s, before the build started at %s", file.Name(), modified, started)
+	}
+	warnf("Stale report file: %s was last modified at %s, before the build started at %s", file.Name(), modified, started)
+	return nil
+}
@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestProcessFileStaleReport(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+
+	data, err := os.ReadFile("../testdata/testng-report.xml")
+	if err != nil {
+		t.Fatalf("Failed to read sample report: %v", err)
+	}
+	stale := filepath.Join(t.TempDir(), "testng-results.xml")
+	if err := os.WriteFile(stale, data, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	modified := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(stale, modified, modified); err != nil {
+		t.Fatalf("Failed to age report: %v", err)
+	}
+
+	t.Setenv("TEST_BUILD_STARTED", strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+
+	tests := []struct {
+		name        string
+		args        Args
+		expectError bool
+		expectWarn  bool
+	}{
+		{name: "Warn", args: Args{BuildStartEnv: "TEST_BUILD_STARTED"}, expectWarn: true},
+		{name: "Fail", args: Args{BuildStartEnv: "TEST_BUILD_STARTED", FailOnStaleReport: true}, expectError: true},
+		{name: "Unset", args: Args{BuildStartEnv: "TEST_BUILD_NOT_SET", FailOnStaleReport: true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			_, err := processFile(stale, tc.args)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("processFile() error = %v, expectError %v", err, tc.expectError)
+			}
+			if err != nil && !strings.Contains(err.Error(), "stale report file") {
+				t.Errorf("Expected a stale report error, got %v", err)
+			}
+
+			warned := false
+			for _, entry := range hook.Entries {
+				if entry.Level == logrus.WarnLevel && strings.HasPrefix(entry.Message, "Stale report file: "+stale) {
+					warned = true
+				}
+			}
+			if warned != tc.expectWarn {
+				t.Errorf("Expected stale report warning %v, got %v", tc.expectWarn, warned)
+			}
+		})
+	}
+
+	// A report written after the build started is fresh
+	t.Setenv("TEST_BUILD_STARTED", time.Now().Add(-3*time.Hour).Format(time.RFC3339))
+	if _, err := processFile(stale, Args{BuildStartEnv: "TEST_BUILD_STARTED", FailOnStaleReport: true}); err != nil {
+		t.Errorf("processFile() unexpected error for a fresh report: %v", err)
+	}
+}
+
+func TestParseReportsSkipsStaleCheck(t *testing.T) {
+	// Comparison reports come from an earlier run and predate the build
+	t.Setenv("TEST_BUILD_STARTED", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	args := Args{BuildStartEnv: "TEST_BUILD_STARTED", FailOnStaleReport: true}
+	reports, err := parseReports("../testdata/compare/before/*.xml", args)
+	if err != nil {
+		t.Fatalf("parseReports() unexpected error: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Errorf("Expected 1 comparison report, got %d", len(reports))
+	}
+}
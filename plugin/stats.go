@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"math"
+	"strconv"
+)
+
+// durationStats accumulates test durations so their mean and standard
+// deviation can be computed without keeping every duration in memory.
+type durationStats struct {
+	Count      int
+	Sum        float64
+	SumSquares float64
+}
+
+// add records a single duration in milliseconds. NaN, infinite and negative
+// durations are skipped so they cannot poison the mean.
+func (s *durationStats) add(ms float64) {
+	if math.IsNaN(ms) || math.IsInf(ms, 0) || ms < 0 {
+		return
+	}
+	s.Count++
+	s.Sum += ms
+	s.SumSquares += ms * ms
+}
+
+// merge folds other into s.
+func (s *durationStats) merge(other durationStats) {
+	s.Count += other.Count
+	s.Sum += other.Sum
+	s.SumSquares += other.SumSquares
+}
+
+// Mean returns the mean duration, or 0 when no durations were recorded.
+func (s durationStats) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// StdDev returns the population standard deviation of the durations.
+func (s durationStats) StdDev() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	mean := s.Mean()
+	// Guard against small negative values caused by rounding
+	return math.Sqrt(math.Max(s.SumSquares/float64(s.Count)-mean*mean, 0))
+}
+
+// reportDurationStats collects the durations of every test in report,
+// skipping tests with an invalid or missing duration.
+func reportDurationStats(report TestNGReport) durationStats {
+	var stats durationStats
+	for _, suite := range report.Suites {
+		for _, class := range suite.Classes {
+			for _, test := range class.Tests {
+				if duration, err := strconv.ParseFloat(test.DurationMS, 64); err == nil {
+					stats.add(duration)
+				}
+			}
+		}
+	}
+	return stats
+}
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"testing"
+)
+
+func TestReportDurationStats(t *testing.T) {
+	var tests []Test
+	for _, duration := range []string{"2", "4", "4", "4", "5", "5", "7", "9", "invalid", "", "NaN", "+Inf", "-3"} {
+		tests = append(tests, Test{Name: "test", Status: "PASS", DurationMS: duration})
+	}
+	report := TestNGReport{Suites: []Suite{{Classes: []Class{{Name: "Class1", Tests: tests}}}}}
+
+	stats := reportDurationStats(report)
+	if stats.Count != 8 {
+		t.Errorf("Expected 8 valid durations, got %d", stats.Count)
+	}
+	if stats.Mean() != 5 {
+		t.Errorf("Expected mean 5, got %f", stats.Mean())
+	}
+	if stats.StdDev() != 2 {
+		t.Errorf("Expected standard deviation 2, got %f", stats.StdDev())
+	}
+
+	// Merging per-file stats gives the same result as a single pass
+	var merged durationStats
+	merged.merge(reportDurationStats(TestNGReport{Suites: []Suite{{Classes: []Class{{Tests: tests[:4]}}}}}))
+	merged.merge(reportDurationStats(TestNGReport{Suites: []Suite{{Classes: []Class{{Tests: tests[4:]}}}}}))
+	if merged != stats {
+		t.Errorf("Merged stats %+v do not match %+v", merged, stats)
+	}
+}
+
+func TestDurationStatsEmpty(t *testing.T) {
+	var stats durationStats
+	if stats.Mean() != 0 || stats.StdDev() != 0 {
+		t.Errorf("Expected zero stats without durations, got mean=%f stddev=%f", stats.Mean(), stats.StdDev())
+	}
+}
@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestUnknownStatuses(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	results, err := processFile("../testdata/statuses/testng-unknown-status.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.Unknown != 2 || results.Total != 4 || results.Failures != 0 || results.Skipped != 1 {
+		t.Errorf("Expected 2 unknown statuses out of 4 tests, got %+v", results)
+	}
+
+	var unknown []string
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "unrecognized status") {
+			unknown = append(unknown, entry.Message)
+		}
+	}
+	expected := []string{
+		"Test 'failure' in class 'com.test.Statuses' has unrecognized status 'FAILURE'",
+		"Test 'numeric' in class 'com.test.Statuses' has unrecognized status '2'",
+	}
+	if diff := cmp.Diff(expected, unknown); diff != "" {
+		t.Errorf("Unknown status log mismatch (-want +got):\n%s", diff)
+	}
+
+	args := Args{ThresholdMode: ThresholdModeAbsolute}
+	if err := validateThresholds(results, args); err != nil {
+		t.Errorf("validateThresholds() unexpected error: %v", err)
+	}
+	args.FailOnUnknownStatus = true
+	err = validateThresholds(results, args)
+	if reason, _ := FailReasonOf(err); reason != FailReasonUnknownStatus {
+		t.Errorf("validateThresholds() expected an unknown status error, got %v", err)
+	}
+
+	// A status configured as a fail status is recognized
+	results, err = processFile("../testdata/statuses/testng-unknown-status.xml", Args{FailStatuses: "FAIL,FAILURE"})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if results.Unknown != 1 || results.Failures != 1 {
+		t.Errorf("Expected 1 unknown status and 1 failure, got %+v", results)
+	}
+}
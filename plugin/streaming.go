@@ -0,0 +1,206 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamThreshold is the file size in bytes above which reports are streamed
+// even when StreamLargeFiles is not set. It is a variable so tests can lower it.
+var streamThreshold int64 = 256 << 20
+
+// errJUnitStream is returned by streamReport for a JUnit report, which is
+// only supported by full parsing.
+var errJUnitStream = errors.New("JUnit reports are not streamed")
+
+// shouldStream reports whether filename should be streamed rather than
+// decoded into a full report tree. Reports are never streamed when the
+// configured outputs or checks need the parsed reports, as the tests of a
+// streamed file would silently be missing from them.
+func shouldStream(filename string, args Args) bool {
+	if needsReports(args) {
+		return false
+	}
+	if args.StreamLargeFiles {
+		return true
+	}
+	info, err := os.Stat(filename)
+	return err == nil && info.Size() > streamThreshold
+}
+
+// streamReport aggregates the report in filename while reading its tokens,
+// so only the test methods of the class being read are held in memory.
+// Groups, dependencies and per-test details need the full tree and are not
+// logged, and the returned fileReport has no parsed Report, so outputs built
+// from the reports do not include the file's tests. A JUnit report accepted
+// with AllowJUnit returns errJUnitStream, so the caller can parse it in full.
+func streamReport(filename string, args Args) (fileReport, []string, []string, error) {
+	file, root, err := openReport(filename, args)
+	if err != nil {
+		return fileReport{}, nil, nil, err
+	}
+	defer file.Close()
+
+	aliases, err := parseSuiteAliases(args.SuiteAliases)
+	if err != nil {
+		return fileReport{}, nil, nil, err
+	}
+
+	fr := fileReport{Path: filename, SuiteResults: make(map[string]Results)}
+	var failedTests, skippedTests []string
+	var reportCounts ReportedCounts
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+	if args.AggregateBy == AggregateByPackage {
+		fr.Packages = make(map[string]Results)
+	}
+
+	var (
+		foundRoot    bool
+		suites       int
+		suite        Suite
+		suiteResults Results
+		class        Class
+	)
+
+	parseErr := func(err error) error {
+		logrus.WithError(err).WithField("File", filename).Error("Failed to parse TestNG XML")
+		return fmt.Errorf("failed to parse TestNG XML for file: %s. Error: %v", filename, err)
+	}
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fileReport{}, nil, nil, parseErr(err)
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			switch {
+			case !foundRoot:
+				if el.Name.Local == root {
+					foundRoot = true
+					reportCounts = attrReportedCounts(el)
+					logrus.Infof("Streaming file %s: group and per-test details are not reported", filename)
+				} else if args.AllowJUnit && isJUnitRoot(el.Name.Local) {
+					return fileReport{}, nil, nil, errJUnitStream
+				}
+			case el.Name.Local == "suite":
+				suites++
+				suite = Suite{
+					Name:           suiteAlias(aliases, attrValue(el, "name")),
+					Duration:       attrValue(el, "duration-ms"),
+					Errors:         attrValue(el, "errors"),
+					ReportedCounts: attrReportedCounts(el),
+				}
+				suiteResults = Results{}
+			case el.Name.Local == "test":
+				suite.Tests = append(suite.Tests, SuiteTest{
+					Name:       attrValue(el, "name"),
+					DurationMS: attrValue(el, "duration-ms"),
+					Errors:     attrValue(el, "errors"),
+				})
+			case el.Name.Local == "class":
+				class = Class{Name: attrValue(el, "name")}
+			case el.Name.Local == "reporter-output":
+				// Test methods are decoded whole, so this is the report's own output
+				var output struct {
+					Lines []string `xml:"line"`
+				}
+				if err := decoder.DecodeElement(&output, &el); err != nil {
+					return fileReport{}, nil, nil, parseErr(err)
+				}
+				if args.LogSystemProperties {
+					fr.SystemProperties = systemProperties(output.Lines)
+				}
+			case el.Name.Local == "test-method":
+				var test Test
+				if err := decoder.DecodeElement(&test, &el); err != nil {
+					return fileReport{}, nil, nil, parseErr(err)
+				}
+				class.Tests = append(class.Tests, test)
+			}
+
+		case xml.EndElement:
+			if !foundRoot {
+				continue
+			}
+			switch el.Name.Local {
+			case "class":
+				results, failed, skipped := aggregateClassResults(class, args)
+				suiteResults.add(results)
+				failedTests = append(failedTests, failed...)
+				skippedTests = append(skippedTests, skipped...)
+				if args.MinClasses > 0 && classExecuted(class, skipStatuses) {
+					fr.ExecutedClasses = append(fr.ExecutedClasses, class.Name)
+				}
+				if fr.Packages != nil {
+					pkg := fr.Packages[classPackage(class.Name)]
+					pkg.add(results)
+					fr.Packages[classPackage(class.Name)] = pkg
+				}
+				for _, test := range class.Tests {
+					if duration, err := strconv.ParseFloat(test.DurationMS, 64); err == nil {
+						fr.Durations.add(duration)
+					}
+				}
+				class = Class{}
+			case "suite":
+				suiteResults.Errors = suiteErrors(suite)
+				suiteResults = finishSuiteResults(suite, suiteResults, args)
+				fr.Results.add(suiteResults)
+
+				total := fr.SuiteResults[suite.Name]
+				total.add(suiteResults)
+				fr.SuiteResults[suite.Name] = total
+
+				logSuiteSummary(suite, suiteResults, args)
+			}
+		}
+	}
+
+	if !foundRoot {
+		return fileReport{}, nil, nil, parseErr(fmt.Errorf("no <%s> element found", root))
+	}
+	if suites == 0 {
+		logrus.Infof("File %s contains no test suites in the XML structure", filename)
+		return fileReport{}, nil, nil, fmt.Errorf("no test suites found in the XML structure of file: %s", filename)
+	}
+
+	if args.PreferReportedCounts {
+		fr.Results = applyReportedCounts(fr.Results, reportCounts)
+	}
+
+	logTestNames(failedTests, skippedTests, args.MaxNamesLogged)
+
+	return fr, failedTests, skippedTests, nil
+}
+
+// attrValue returns the value of the named attribute of el, or "" when absent.
+func attrValue(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// attrReportedCounts reads the optional count attributes of el.
+func attrReportedCounts(el xml.StartElement) ReportedCounts {
+	return ReportedCounts{
+		ReportedTotal:   attrValue(el, "total"),
+		ReportedPassed:  attrValue(el, "passed"),
+		ReportedFailed:  attrValue(el, "failed"),
+		ReportedSkipped: attrValue(el, "skipped"),
+	}
+}
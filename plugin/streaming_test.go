@@ -0,0 +1,212 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestStreamReportMatchesParseReport(t *testing.T) {
+	tests := []struct {
+		file string
+		args Args
+	}{
+		{file: "../testdata/testng-report.xml"},
+		{file: "../testdata/errors/testng-errors.xml"},
+		{file: "../testdata/config/testng-config-skips.xml"},
+		{file: "../testdata/failures/testng-failure-kinds.xml"},
+		{file: "../testdata/skips/testng-skipped-classes.xml"},
+		{file: "../testdata/durations/testng-test-durations.xml", args: Args{UseSuiteDuration: true}},
+		{file: "../testdata/counts/testng-root-counts.xml", args: Args{PreferReportedCounts: true}},
+		{file: "../testdata/counts/testng-suite-counts.xml", args: Args{PreferReportedCounts: true}},
+		{file: "../testdata/root/ngtest-results.xml", args: Args{RootElement: "ngtest-results"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(filepath.Base(tc.file), func(t *testing.T) {
+			report, err := parseReport(tc.file, tc.args)
+			if err != nil {
+				t.Fatalf("parseReport() unexpected error: %v", err)
+			}
+			expected := logTestNGReportDetails(report, tc.args)
+
+			fr, failed, skipped, err := streamReport(tc.file, tc.args)
+			if err != nil {
+				t.Fatalf("streamReport() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(expected.Results, fr.Results); diff != "" {
+				t.Errorf("Results mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(expected.SuiteResults, fr.SuiteResults); diff != "" {
+				t.Errorf("Suite results mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(expected.FailedTests, failed); diff != "" {
+				t.Errorf("Failed tests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(expected.SkippedTests, skipped); diff != "" {
+				t.Errorf("Skipped tests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(reportDurationStats(report), fr.Durations); diff != "" {
+				t.Errorf("Duration stats mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStreamReportErrors(t *testing.T) {
+	for _, file := range []string{"../testdata/invalid.xml", "../testdata/root/ngtest-results.xml"} {
+		if _, _, _, err := streamReport(file, Args{}); err == nil {
+			t.Errorf("streamReport(%s) expected an error", file)
+		}
+	}
+}
+
+func TestProcessFileStreamsAboveThreshold(t *testing.T) {
+	defer func(threshold int64) { streamThreshold = threshold }(streamThreshold)
+	streamThreshold = 0
+
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	results, err := processFile("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(Results{Total: 3, Failures: 1, DurationMS: 15, AssertionFailures: 1}, results); diff != "" {
+		t.Errorf("processFile() mismatch (-want +got):\n%s", diff)
+	}
+	found := false
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "Streaming file ../testdata/testng-report.xml") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the file to be streamed")
+	}
+}
+
+func TestProcessFilesDoesNotStreamParsedReportOutputs(t *testing.T) {
+	defer func(threshold int64) { streamThreshold = threshold }(streamThreshold)
+	streamThreshold = 0
+
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	// The CSV output is built from the parsed reports
+	args := Args{StreamLargeFiles: true, CSVFile: filepath.Join(t.TempDir(), "results.csv")}
+	agg := processFiles([]string{"../testdata/testng-report.xml"}, args)
+	if len(agg.Reports) != 1 || len(agg.Reports[0].Report.Suites) != 1 {
+		t.Fatalf("Expected the parsed report to be kept, got %+v", agg.Reports)
+	}
+
+	warned := false
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "Streaming file") {
+			t.Errorf("Expected the file not to be streamed, got %q", entry.Message)
+		}
+		if entry.Level == logrus.WarnLevel && strings.HasPrefix(entry.Message, "StreamLargeFiles is ignored") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("Expected a warning that StreamLargeFiles is ignored")
+	}
+}
+
+func TestProcessFilesStreamingJUnitReport(t *testing.T) {
+	files := []string{"../testdata/junit/junit-report.xml"}
+	expected := processFiles(files, Args{AllowJUnit: true}).Results
+
+	agg := processFiles(files, Args{AllowJUnit: true, StreamLargeFiles: true})
+	if len(agg.SkippedFiles) > 0 {
+		t.Fatalf("Unexpected skipped files: %v", agg.SkippedFiles)
+	}
+	if diff := cmp.Diff(expected, agg.Results); diff != "" {
+		t.Errorf("Streamed JUnit results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// writeSyntheticReport writes a report with classes × methods test methods.
+func writeSyntheticReport(b *testing.B, classes, methods int) string {
+	b.Helper()
+	filename := filepath.Join(b.TempDir(), "testng-results.xml")
+	file, err := os.Create(filename)
+	if err != nil {
+		b.Fatalf("Failed to create synthetic report: %v", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintln(file, `<testng-results><suite name="Synthetic"><test name="Synthetic">`)
+	for c := 0; c < classes; c++ {
+		fmt.Fprintf(file, "<class name=\"com.test.Class%d\">\n", c)
+		for m := 0; m < methods; m++ {
+			status := "PASS"
+			if m%100 == 0 {
+				status = "FAIL"
+			}
+			fmt.Fprintf(file, "<test-method name=\"test%d\" status=\"%s\" duration-ms=\"1\"/>\n", m, status)
+		}
+		fmt.Fprintln(file, "</class>")
+	}
+	fmt.Fprintln(file, `</test></suite></testng-results>`)
+	return filename
+}
+
+// benchmarkHeap runs process b.N times and reports the live heap held by its
+// result after a garbage collection.
+func benchmarkHeap(b *testing.B, process func() interface{}) {
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(os.Stderr)
+
+	var peak uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := process()
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > peak {
+			peak = stats.HeapAlloc
+		}
+		runtime.KeepAlive(result)
+	}
+	b.ReportMetric(float64(peak)/(1<<20), "heap-MB")
+}
+
+// BenchmarkStreamReport shows that streaming holds a flat amount of memory
+// while decoding the whole report grows with the number of test methods.
+func BenchmarkStreamReport(b *testing.B) {
+	for _, classes := range []int{100, 1000} {
+		filename := writeSyntheticReport(b, classes, 200)
+		args := Args{}
+
+		b.Run(fmt.Sprintf("Stream/%d", classes*200), func(b *testing.B) {
+			benchmarkHeap(b, func() interface{} {
+				fr, _, _, err := streamReport(filename, args)
+				if err != nil {
+					b.Fatalf("streamReport() unexpected error: %v", err)
+				}
+				return fr
+			})
+		})
+		b.Run(fmt.Sprintf("Decode/%d", classes*200), func(b *testing.B) {
+			benchmarkHeap(b, func() interface{} {
+				report, err := parseReport(filename, args)
+				if err != nil {
+					b.Fatalf("parseReport() unexpected error: %v", err)
+				}
+				return report
+			})
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdout receives the JSON summary when JSONStdout is set. It is a variable
+// so tests can capture the output.
+var stdout io.Writer = os.Stdout
+
+// Summary is the JSON representation of the aggregated plugin results.
+type Summary struct {
+	Total      int            `json:"total"`
+	Failures   int            `json:"failures"`
+	Skipped    int            `json:"skipped"`
+	Errors     int            `json:"errors"`
+	DurationMS float64        `json:"duration_ms"`
+	Suites     []SuiteSummary `json:"suites,omitempty"`
+	Files      []FileSummary  `json:"files,omitempty"`
+}
+
+// FileSummary is the JSON representation of the results of a single
+// processed report file.
+type FileSummary struct {
+	Path        string   `json:"path"`
+	Results     Results  `json:"results"`
+	FailedTests []string `json:"failed_tests"`
+}
+
+// SuiteSummary is the JSON representation of a single suite.
+type SuiteSummary struct {
+	Name       string         `json:"name"`
+	Total      int            `json:"total"`
+	Failures   int            `json:"failures"`
+	Skipped    int            `json:"skipped"`
+	Errors     int            `json:"errors"`
+	DurationMS float64        `json:"duration_ms"`
+	Classes    []ClassSummary `json:"classes"`
+	Tests      []TestSummary  `json:"tests"`
+}
+
+// ClassSummary is the JSON representation of the results of a single class.
+type ClassSummary struct {
+	Name       string  `json:"name"`
+	Total      int     `json:"total"`
+	Passed     int     `json:"passed"`
+	Failures   int     `json:"failures"`
+	Skipped    int     `json:"skipped"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// TestSummary is the JSON representation of a single test-method.
+type TestSummary struct {
+	Name          string   `json:"name"`
+	Class         string   `json:"class"`
+	Status        string   `json:"status"`
+	DurationMS    string   `json:"duration_ms"`
+	Exception     string   `json:"exception,omitempty"`
+	Groups        []string `json:"groups"`
+	RetryAnalyzer string   `json:"retry_analyzer,omitempty"`
+}
+
+// buildSummary builds the JSON summary from the aggregated results. Per-suite
+// and per-test details are only included when DetailedJSON is set.
+func buildSummary(results Results, reports []fileReport, args Args) Summary {
+	summary := Summary{
+		Total:      results.Total,
+		Failures:   results.Failures,
+		Skipped:    results.Skipped,
+		Errors:     results.Errors,
+		DurationMS: results.DurationMS,
+	}
+	if !args.DetailedJSON {
+		return summary
+	}
+
+	for _, fr := range reports {
+		failedTests := fr.FailedTests
+		if failedTests == nil {
+			failedTests = []string{}
+		}
+		summary.Files = append(summary.Files, FileSummary{Path: fr.Path, Results: fr.Results, FailedTests: failedTests})
+
+		for i, suite := range fr.Report.Suites {
+			suiteResults, classResults := fr.Suites[i].Results, fr.Suites[i].ClassResults
+			groups := suiteTestGroups(suite)
+
+			ss := SuiteSummary{
+				Name:       suite.Name,
+				Total:      suiteResults.Total,
+				Failures:   suiteResults.Failures,
+				Skipped:    suiteResults.Skipped,
+				Errors:     suiteResults.Errors,
+				DurationMS: suiteResults.DurationMS,
+				Classes:    []ClassSummary{},
+				Tests:      []TestSummary{},
+			}
+			for i, class := range suite.Classes {
+				res := classResults[i]
+				ss.Classes = append(ss.Classes, ClassSummary{
+					Name:       class.Name,
+					Total:      res.Total,
+					Passed:     res.Total - res.Failures - res.Skipped,
+					Failures:   res.Failures,
+					Skipped:    res.Skipped,
+					DurationMS: res.DurationMS,
+				})
+
+				for _, test := range class.Tests {
+					testGroups := groups[class.Name+"."+test.Name]
+					if testGroups == nil {
+						testGroups = []string{}
+					}
+					ss.Tests = append(ss.Tests, TestSummary{
+						Name:          test.Name,
+						Class:         class.Name,
+						Status:        test.Status,
+						DurationMS:    test.DurationMS,
+						Exception:     test.Exception,
+						Groups:        testGroups,
+						RetryAnalyzer: test.RetryAnalyzer,
+					})
+				}
+			}
+			summary.Suites = append(summary.Suites, ss)
+		}
+	}
+
+	return summary
+}
+
+// suiteTestGroups maps each "class.method" in a suite to the groups it belongs to.
+func suiteTestGroups(suite Suite) map[string][]string {
+	groups := make(map[string][]string)
+	for _, group := range suite.Groups {
+		for _, method := range group.Methods {
+			key := method.ClassName + "." + method.Name
+			groups[key] = append(groups[key], group.Name)
+		}
+	}
+	return groups
+}
+
+// writeJSONSummary writes the JSON summary to filename.
+func writeJSONSummary(filename string, summary Summary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON summary: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON summary to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeJSONStdout writes the summary to stdout as a single line of JSON, so
+// it can be piped to tools such as jq. Logs are written to stderr.
+func writeJSONStdout(summary Summary) error {
+	if err := json.NewEncoder(stdout).Encode(summary); err != nil {
+		return fmt.Errorf("failed to write JSON summary to stdout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+// newSummaryFileReport returns report as processFiles keeps it for outputs,
+// with the results of each suite.
+func newSummaryFileReport(path string, report TestNGReport, args Args) fileReport {
+	return fileReport{Path: path, Report: report, Suites: logTestNGReportDetails(report, args).Suites}
+}
+
+func TestWriteJSONSummaryGroups(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	reports := []fileReport{newSummaryFileReport("testng-report.xml", report, Args{})}
+	results := Results{Total: 3, Failures: 1, DurationMS: 15}
+	output := filepath.Join(t.TempDir(), "summary.json")
+	if err := writeJSONSummary(output, buildSummary(results, reports, Args{DetailedJSON: true})); err != nil {
+		t.Fatalf("writeJSONSummary() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read JSON summary: %v", err)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Failed to decode JSON summary: %v", err)
+	}
+	if len(summary.Suites) != 1 {
+		t.Fatalf("Expected 1 suite, got %d", len(summary.Suites))
+	}
+
+	got := make(map[string][]string)
+	for _, test := range summary.Suites[0].Tests {
+		got[test.Name] = test.Groups
+	}
+	expected := map[string][]string{
+		"test1": {"group1"},
+		"test2": {"group1", "group2"},
+		"setUp": {},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Test groups mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildSummaryClasses(t *testing.T) {
+	report, err := parseReport("../testdata/skips/testng-skipped-classes.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	reports := []fileReport{newSummaryFileReport("testng-skipped-classes.xml", report, Args{})}
+
+	// The per-class results come from processing, so nothing is logged again
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+	summary := buildSummary(Results{Total: 4, Skipped: 3, DurationMS: 5}, reports, Args{DetailedJSON: true})
+	if len(hook.Entries) != 0 {
+		t.Errorf("Expected buildSummary() to log nothing, got %+v", hook.Entries)
+	}
+	if len(summary.Suites) != 1 {
+		t.Fatalf("Expected 1 suite, got %d", len(summary.Suites))
+	}
+
+	expected := []ClassSummary{
+		{Name: "com.test.Disabled", Total: 2, Passed: 0, Failures: 0, Skipped: 2, DurationMS: 0},
+		{Name: "com.test.Mixed", Total: 2, Passed: 1, Failures: 0, Skipped: 1, DurationMS: 5},
+	}
+	if diff := cmp.Diff(expected, summary.Suites[0].Classes); diff != "" {
+		t.Errorf("Classes mismatch (-want +got):\n%s", diff)
+	}
+
+	// The classes array is encoded in the detailed JSON
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("Failed to encode summary: %v", err)
+	}
+	var decoded struct {
+		Suites []struct {
+			Classes []map[string]interface{} `json:"classes"`
+		} `json:"suites"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode summary: %v", err)
+	}
+	mixed := decoded.Suites[0].Classes[1]
+	if mixed["name"] != "com.test.Mixed" || mixed["passed"] != 1.0 || mixed["failures"] != 0.0 || mixed["duration_ms"] != 5.0 {
+		t.Errorf("Unexpected JSON for class: %v", mixed)
+	}
+}
+
+func TestBuildSummaryWithoutDetails(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	results := Results{Total: 3, Failures: 1, DurationMS: 15}
+	summary := buildSummary(results, []fileReport{{Report: report}}, Args{})
+
+	expected := Summary{Total: 3, Failures: 1, DurationMS: 15}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Errorf("buildSummary() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSuiteTestGroupsWithTestScopedGroups(t *testing.T) {
+	report, err := parseReport("../testdata/groups/testng-test-groups.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	suite := report.Suites[0]
+	var names []string
+	for _, group := range suite.Groups {
+		names = append(names, group.Name)
+	}
+	if diff := cmp.Diff([]string{"group1", "group3"}, names); diff != "" {
+		t.Errorf("Merged groups mismatch (-want +got):\n%s", diff)
+	}
+
+	expected := map[string][]string{
+		"com.test.TestOne.test1": {"group1"},
+		"com.test.TestOne.test2": {"group1"},
+		"com.test.TestOne.test3": {"group3"},
+	}
+	if diff := cmp.Diff(expected, suiteTestGroups(suite)); diff != "" {
+		t.Errorf("Test groups mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecJSONStdout(t *testing.T) {
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		JSONStdout:            true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to decode JSON from stdout %q: %v", buf.String(), err)
+	}
+
+	expected := Summary{Total: 3, Failures: 1, DurationMS: 15}
+	if diff := cmp.Diff(expected, summary); diff != "" {
+		t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecJSONSummaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "reports.txt")
+	reports := "../testdata/testng-report.xml\n../testdata/flaky/testng-flaky.xml\n"
+	if err := os.WriteFile(manifest, []byte(reports), 0644); err != nil {
+		t.Fatalf("Failed to write file list: %v", err)
+	}
+
+	output := filepath.Join(dir, "summary.json")
+	args := Args{
+		FileList:      manifest,
+		ThresholdMode: ThresholdModeAbsolute,
+		OutputFile:    output,
+		DetailedJSON:  true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read JSON summary: %v", err)
+	}
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Failed to decode JSON summary: %v", err)
+	}
+
+	type fileEntry struct {
+		Path        string
+		Total       int
+		Failures    int
+		FailedTests []string
+	}
+	var got []fileEntry
+	for _, file := range summary.Files {
+		got = append(got, fileEntry{file.Path, file.Results.Total, file.Results.Failures, file.FailedTests})
+	}
+	expected := []fileEntry{
+		{Path: "../testdata/flaky/testng-flaky.xml", Total: 5, Failures: 1, FailedTests: []string{"refund"}},
+		{Path: "../testdata/testng-report.xml", Total: 3, Failures: 1, FailedTests: []string{"test1"}},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecOutputOnFailureOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		failedFails int
+		expectErr   bool
+	}{
+		// The sample report has a failure rate of 33.33%
+		{name: "Passing", failedFails: 50},
+		{name: "Failing", failedFails: 10, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			output := filepath.Join(dir, "summary.json")
+			csvFile := filepath.Join(dir, "results.csv")
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModePercentage,
+				FailedFails:           tc.failedFails,
+				OutputFile:            output,
+				CSVFile:               csvFile,
+				OutputOnFailureOnly:   true,
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Exec() error = %v, expectErr %v", err, tc.expectErr)
+			}
+
+			for _, filename := range []string{output, csvFile} {
+				_, statErr := os.Stat(filename)
+				if written := statErr == nil; written != tc.expectErr {
+					t.Errorf("File %s written = %v, want %v", filepath.Base(filename), written, tc.expectErr)
+				}
+			}
+		})
+	}
+}
+
+func TestExecOutputOnFailureOnlyAppendsHistory(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	args := Args{
+		ReportFilenamePattern: "../testdata/testng-report.xml",
+		ThresholdMode:         ThresholdModePercentage,
+		FailedFails:           50,
+		HistoryFile:           historyFile,
+		OutputOnFailureOnly:   true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	history, err := readHistory(historyFile)
+	if err != nil {
+		t.Fatalf("readHistory() unexpected error: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("Expected the passing run to be appended to the history file, got %d records", len(history))
+	}
+}
+
+func TestExecJSONSummaryDoesNotRepeatWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/flaky/testng-flaky.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+		OutputFile:            filepath.Join(t.TempDir(), "summary.json"),
+		JSONStdout:            true,
+		DetailedJSON:          true,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	// Building the summaries reuses the results of processing the report
+	counts := make(map[string]int)
+	for _, entry := range hook.Entries {
+		if strings.HasPrefix(entry.Message, "Flaky test ") || strings.Contains(entry.Message, "failed without an exception") {
+			counts[entry.Message]++
+		}
+	}
+	for message, count := range counts {
+		if count != 1 {
+			t.Errorf("Expected %q to be logged once, got %d", message, count)
+		}
+	}
+	if len(counts) != 2 {
+		t.Errorf("Expected 2 flaky test messages, got %v", counts)
+	}
+}
@@ -0,0 +1,37 @@
+package plugin
+
+import "strings"
+
+// Status symbols prefixed to test lines when StatusSymbols is set, with
+// ASCII fallbacks for terminals lacking Unicode.
+const (
+	symbolPass      = "✓"
+	symbolFail      = "✗"
+	symbolSkip      = "−"
+	asciiSymbolPass = "+"
+	asciiSymbolFail = "x"
+	asciiSymbolSkip = "-"
+)
+
+// statusSymbol returns the symbol for a test status, classified with the
+// configured fail and skip statuses.
+func statusSymbol(status string, args Args) string {
+	status = strings.ToUpper(status)
+	switch {
+	case statusSet(args.FailStatuses, DefaultFailStatuses)[status]:
+		if args.ASCIIStatusSymbols {
+			return asciiSymbolFail
+		}
+		return symbolFail
+	case statusSet(args.SkipStatuses, DefaultSkipStatuses)[status]:
+		if args.ASCIIStatusSymbols {
+			return asciiSymbolSkip
+		}
+		return symbolSkip
+	default:
+		if args.ASCIIStatusSymbols {
+			return asciiSymbolPass
+		}
+		return symbolPass
+	}
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogSuiteTestDetailsStatusSymbols(t *testing.T) {
+	suite := Suite{
+		Name: "TestSuite",
+		Classes: []Class{
+			{
+				Name: "Class1",
+				Tests: []Test{
+					{Name: "Test1", Status: "PASS", DurationMS: "10"},
+					{Name: "Test2", Status: "FAIL", DurationMS: "20"},
+					{Name: "Test3", Status: "SKIP", DurationMS: "0"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected []string
+	}{
+		{
+			name: "Disabled",
+			args: Args{},
+			expected: []string{
+				"\nTest Details:",
+				"\n- Test: Test1 | Status: PASS | Duration: 10 ms",
+				"\n- Test: Test2 | Status: FAIL | Duration: 20 ms",
+				"\n- Test: Test3 | Status: SKIP | Duration: 0 ms",
+			},
+		},
+		{
+			name: "Unicode",
+			args: Args{StatusSymbols: true},
+			expected: []string{
+				"\nTest Details:",
+				"\n- ✓ Test: Test1 | Status: PASS | Duration: 10 ms",
+				"\n- ✗ Test: Test2 | Status: FAIL | Duration: 20 ms",
+				"\n- − Test: Test3 | Status: SKIP | Duration: 0 ms",
+			},
+		},
+		{
+			name: "ASCII",
+			args: Args{StatusSymbols: true, ASCIIStatusSymbols: true},
+			expected: []string{
+				"\nTest Details:",
+				"\n- + Test: Test1 | Status: PASS | Duration: 10 ms",
+				"\n- x Test: Test2 | Status: FAIL | Duration: 20 ms",
+				"\n- - Test: Test3 | Status: SKIP | Duration: 0 ms",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logrus.SetLevel(logrus.InfoLevel)
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			logSuiteTestDetails(suite, tc.args)
+
+			var got []string
+			for _, entry := range hook.Entries {
+				got = append(got, entry.Message)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Log messages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
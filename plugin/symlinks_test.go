@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLocateFilesSymlinks(t *testing.T) {
+	data, err := os.ReadFile("../testdata/testng-report.xml")
+	if err != nil {
+		t.Fatalf("Failed to read sample report: %v", err)
+	}
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "outside.xml")
+	if err := os.WriteFile(outside, data, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	report := filepath.Join(dir, "report.xml")
+	if err := os.WriteFile(report, data, 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	link := filepath.Join(dir, "linked.xml")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("Symlinks are not supported: %v", err)
+	}
+
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	files, err := locateFiles(filepath.Join(dir, "*.xml"), Args{})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{report}, files); diff != "" {
+		t.Errorf("locateFiles() mismatch (-want +got):\n%s", diff)
+	}
+
+	skipped := false
+	for _, entry := range hook.Entries {
+		if strings.Contains(entry.Message, "Skipping symlinked report file: "+link) {
+			skipped = true
+		}
+	}
+	if !skipped {
+		t.Errorf("Expected the skipped symlink to be logged")
+	}
+
+	files, err = locateFiles(filepath.Join(dir, "*.xml"), Args{FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("locateFiles() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{link, report}, files); diff != "" {
+		t.Errorf("locateFiles() with FollowSymlinks mismatch (-want +got):\n%s", diff)
+	}
+}
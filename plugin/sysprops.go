@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// systemPropertyKeys are the system properties logged for reproducibility,
+// in the order they are logged.
+var systemPropertyKeys = []string{
+	"java.version",
+	"java.vendor",
+	"java.vm.name",
+	"os.name",
+	"os.version",
+	"os.arch",
+	"user.timezone",
+	"file.encoding",
+}
+
+// systemProperties extracts the known system properties from the lines of
+// the report's <reporter-output>, where suites may log them as key=value or
+// key: value. It returns them as key=value pairs in systemPropertyKeys order.
+func systemProperties(lines []string) []string {
+	values := make(map[string]string)
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, seen := values[key]; !seen {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+
+	var props []string
+	for _, key := range systemPropertyKeys {
+		if value, ok := values[key]; ok && value != "" {
+			props = append(props, key+"="+value)
+		}
+	}
+	return props
+}
+
+// logSystemProperties logs the system properties the tests ran with.
+func logSystemProperties(props []string) {
+	logrus.Infof("\nSystem Properties: %s", strings.Join(props, ", "))
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestExecLogSystemProperties(t *testing.T) {
+	// Only one of the reports carries system properties
+	fileList := filepath.Join(t.TempDir(), "files.txt")
+	manifest := "../testdata/sysprops/testng-system-properties.xml\n../testdata/testng-report.xml\n"
+	if err := os.WriteFile(fileList, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write file list: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		expected []string
+	}{
+		{
+			name:     "Present",
+			args:     Args{FileList: fileList, LogSystemProperties: true},
+			expected: []string{"\nSystem Properties: java.version=17.0.9, java.vendor=Eclipse Adoptium, os.name=Linux, os.arch=amd64"},
+		},
+		{
+			name:     "Streamed",
+			args:     Args{FileList: fileList, LogSystemProperties: true, StreamLargeFiles: true},
+			expected: []string{"\nSystem Properties: java.version=17.0.9, java.vendor=Eclipse Adoptium, os.name=Linux, os.arch=amd64"},
+		},
+		{
+			name: "Absent",
+			args: Args{ReportFilenamePattern: "../testdata/testng-report.xml", LogSystemProperties: true},
+		},
+		{
+			name: "Disabled",
+			args: Args{FileList: fileList},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logrus.SetLevel(logrus.InfoLevel)
+			hook := NewMockLogHook()
+			logrus.AddHook(hook)
+
+			args := tc.args
+			args.ThresholdMode = ThresholdModeAbsolute
+			if err := Exec(context.Background(), args); err != nil {
+				t.Fatalf("Exec() unexpected error: %v", err)
+			}
+
+			var logged []string
+			for _, entry := range hook.Entries {
+				if strings.Contains(entry.Message, "System Properties:") {
+					logged = append(logged, entry.Message)
+				}
+			}
+			if diff := cmp.Diff(tc.expected, logged); diff != "" {
+				t.Errorf("System properties log mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
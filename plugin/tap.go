@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tapDiagnostic is the YAML diagnostic block written after a failed test.
+type tapDiagnostic struct {
+	Message   string `yaml:"message"`
+	Severity  string `yaml:"severity"`
+	Exception string `yaml:"exception,omitempty"`
+}
+
+// tapEscaper escapes the characters TAP gives a meaning in a test point
+// description: # starts a directive and \ escapes.
+var tapEscaper = strings.NewReplacer(`\`, `\\`, "#", `\#`)
+
+// buildTAP renders the processed reports as a TAP version 13 stream with one
+// test point per test-method. Skipped tests carry a SKIP directive.
+func buildTAP(reports []fileReport, args Args) ([]byte, error) {
+	failStatuses := statusSet(args.FailStatuses, DefaultFailStatuses)
+	skipStatuses := statusSet(args.SkipStatuses, DefaultSkipStatuses)
+
+	var points bytes.Buffer
+	n := 0
+	for _, fr := range reports {
+		for _, suite := range fr.Report.Suites {
+			for _, class := range suite.Classes {
+				for _, test := range class.Tests {
+					n++
+					name := tapEscaper.Replace(class.Name + "." + test.Name)
+					status := strings.ToUpper(test.Status)
+
+					switch {
+					case failStatuses[status]:
+						fmt.Fprintf(&points, "not ok %d - %s\n", n, name)
+						diag, err := yaml.Marshal(tapDiagnostic{
+							Message:   firstLine(test.Exception),
+							Severity:  "fail",
+							Exception: strings.TrimSpace(test.Exception),
+						})
+						if err != nil {
+							return nil, fmt.Errorf("failed to encode TAP diagnostic for test %s: %w", name, err)
+						}
+						points.WriteString("  ---\n")
+						for _, line := range strings.Split(strings.TrimRight(string(diag), "\n"), "\n") {
+							points.WriteString("  " + line + "\n")
+						}
+						points.WriteString("  ...\n")
+					case skipStatuses[status]:
+						fmt.Fprintf(&points, "ok %d - %s # SKIP\n", n, name)
+					default:
+						fmt.Fprintf(&points, "ok %d - %s\n", n, name)
+					}
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "TAP version 13\n1..%d\n", n)
+	out.Write(points.Bytes())
+	return out.Bytes(), nil
+}
+
+// writeTAP writes the processed reports to filename as a TAP version 13 stream.
+func writeTAP(filename string, reports []fileReport, args Args) error {
+	data, err := buildTAP(reports, args)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write TAP stream to %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteTAP(t *testing.T) {
+	report, err := parseReport("../testdata/testng-report.xml", Args{})
+	if err != nil {
+		t.Fatalf("parseReport() unexpected error: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "results.tap")
+	if err := writeTAP(output, []fileReport{{Report: report}}, Args{}); err != nil {
+		t.Fatalf("writeTAP() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read TAP stream: %v", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if lines[0] != "TAP version 13" || lines[1] != "1..3" {
+		t.Errorf("Expected a TAP 13 header with a plan of 3 tests, got %q", lines[:2])
+	}
+
+	expected := []string{
+		"not ok 1 - com.test.TestOne.test1",
+		"  ---",
+		"  message: java.lang.AssertionError",
+		"  severity: fail",
+		"  exception: |-",
+		"  ...",
+		"ok 2 - com.test.TestOne.test2",
+		"ok 3 - com.test.TestOne.setUp",
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("TAP stream is missing line %q:\n%s", line, data)
+		}
+	}
+}
+
+func TestBuildTAPEscapesDescriptions(t *testing.T) {
+	report := TestNGReport{Suites: []Suite{{Classes: []Class{{
+		Name: "com.test.Issue",
+		Tests: []Test{
+			{Name: "test#42", Status: "SKIP"},
+			{Name: `path\to`, Status: "PASS"},
+		},
+	}}}}}
+
+	data, err := buildTAP([]fileReport{{Report: report}}, Args{})
+	if err != nil {
+		t.Fatalf("buildTAP() unexpected error: %v", err)
+	}
+
+	expected := "TAP version 13\n1..2\n" +
+		"ok 1 - com.test.Issue.test\\#42 # SKIP\n" +
+		"ok 2 - com.test.Issue.path\\\\to\n"
+	if diff := cmp.Diff(expected, string(data)); diff != "" {
+		t.Errorf("TAP stream mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -1,19 +1,121 @@
 package plugin
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
 
-// TestNGReport represents the structure of a TestNG XML report.
+// TestNGReport represents the structure of a TestNG XML report. The root
+// element is located by name when the report is parsed, see RootElement.
 type TestNGReport struct {
-	XMLName xml.Name `xml:"testng-results"`
-	Suites  []Suite  `xml:"suite"`
+	XMLName xml.Name
+	Suites  []Suite `xml:"suite"`
+	ReportedCounts
+
+	// ReporterOutput holds the lines logged with Reporter.log outside of
+	// any test method.
+	ReporterOutput []string `xml:"reporter-output>line"`
+}
+
+// ReportedCounts holds the optional count attributes some producers write on
+// the <testng-results> and <suite> elements. Empty values mean the attribute
+// was absent.
+type ReportedCounts struct {
+	ReportedTotal   string `xml:"total,attr"`
+	ReportedPassed  string `xml:"passed,attr"`
+	ReportedFailed  string `xml:"failed,attr"`
+	ReportedSkipped string `xml:"skipped,attr"`
 }
 
 // Suite represents a TestNG suite.
 type Suite struct {
-	Name     string  `xml:"name,attr"`
-	Duration string  `xml:"duration-ms,attr"`
-	Groups   []Group `xml:"groups>group"`
-	Classes  []Class `xml:"test>class"`
+	Name     string      `xml:"name,attr"`
+	Duration string      `xml:"duration-ms,attr"`
+	Errors   string      `xml:"errors,attr"`
+	Groups   []Group     `xml:"groups>group"`
+	Tests    []SuiteTest `xml:"test"`
+	ReportedCounts
+
+	// Parallel and ThreadCount hold the suite's parallelism settings. Empty
+	// values mean the attribute was absent.
+	Parallel    string `xml:"parallel,attr"`
+	ThreadCount string `xml:"thread-count,attr"`
+
+	// ConfigFailurePolicy is the suite's configfailurepolicy, when the
+	// producer records it.
+	ConfigFailurePolicy string `xml:"config-failure-policy,attr"`
+
+	// DirectClasses holds the classes placed directly under the suite by
+	// producers that omit the <test> wrapper.
+	DirectClasses []Class `xml:"class"`
+
+	// Classes holds the classes of every <test> in the suite, followed by
+	// its direct classes. It is populated when the suite is decoded.
+	Classes []Class `xml:"-"`
+}
+
+// UnmarshalXML decodes a suite, collects the classes of its <test> elements
+// and those placed directly under it, and merges groups declared at <test>
+// scope into the suite's groups.
+func (s *Suite) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type suite Suite // prevents recursion into UnmarshalXML
+	var raw suite
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	*s = Suite(raw)
+	for _, test := range s.Tests {
+		s.Classes = append(s.Classes, test.Classes...)
+		s.Groups = mergeGroups(s.Groups, test.Groups)
+	}
+	s.Classes = append(s.Classes, s.DirectClasses...)
+	return nil
+}
+
+// mergeGroups merges groups into existing, combining the methods of groups
+// that share a name and dropping duplicate methods.
+func mergeGroups(existing, groups []Group) []Group {
+	for _, group := range groups {
+		idx := -1
+		for i := range existing {
+			if existing[i].Name == group.Name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			existing = append(existing, Group{Name: group.Name})
+			idx = len(existing) - 1
+		}
+
+		for _, method := range group.Methods {
+			if !containsMethod(existing[idx].Methods, method) {
+				existing[idx].Methods = append(existing[idx].Methods, method)
+			}
+		}
+	}
+	return existing
+}
+
+// containsMethod reports whether methods contains method.
+func containsMethod(methods []Method, method Method) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// SuiteTest represents a <test> element within a TestNG suite.
+type SuiteTest struct {
+	Name       string  `xml:"name,attr"`
+	DurationMS string  `xml:"duration-ms,attr"`
+	Errors     string  `xml:"errors,attr"`
+	Groups     []Group `xml:"groups>group"`
+	Classes    []Class `xml:"class"`
 }
 
 // Group represents a TestNG group.
@@ -42,13 +144,148 @@ type Test struct {
 	DurationMS  string `xml:"duration-ms,attr"`
 	IsConfig    bool   `xml:"is-config,attr"`
 	Description string `xml:"description,attr"`
-	Exception   string `xml:"exception>short-stacktrace"`
+
+	// DependsOnGroups is the comma-separated list of groups the test depends on.
+	DependsOnGroups string `xml:"depends-on-groups,attr"`
+
+	// ConfigFailurePolicy is the configfailurepolicy in effect for a
+	// configuration method, when the producer records it.
+	ConfigFailurePolicy string `xml:"config-failure-policy,attr"`
+
+	// StartedAt and FinishedAt are the timestamps of the test method as
+	// written by the producer.
+	StartedAt  string `xml:"started-at,attr"`
+	FinishedAt string `xml:"finished-at,attr"`
+
+	// Assertions is the number of assertions the test made, when the
+	// producer records it.
+	Assertions string `xml:"assertions,attr"`
+
+	// Retried is set on an attempt that a retry analyzer discarded and ran again.
+	Retried bool `xml:"retried,attr"`
+
+	// RetryAnalyzer names the retry analyzer of the test, when the producer
+	// records it as a retry-analyzer attribute or element.
+	RetryAnalyzer string `xml:"retry-analyzer,attr"`
+
+	// Exception and ExceptionClass hold the short stack trace and class of
+	// the <exception> element. They are populated when the test is decoded.
+	Exception      string `xml:"-"`
+	ExceptionClass string `xml:"-"`
+}
+
+// UnmarshalXML decodes a test method, the class and short stack trace of its
+// exception and its retry analyzer.
+func (t *Test) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type test Test // prevents recursion into UnmarshalXML
+	var raw struct {
+		test
+		RawException *struct {
+			Class           string `xml:"class,attr"`
+			ShortStacktrace string `xml:"short-stacktrace"`
+		} `xml:"exception"`
+		RawRetryAnalyzer string `xml:"retry-analyzer"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	*t = Test(raw.test)
+	if raw.RawException != nil {
+		t.Exception = raw.RawException.ShortStacktrace
+		t.ExceptionClass = raw.RawException.Class
+	}
+	if t.RetryAnalyzer == "" {
+		t.RetryAnalyzer = strings.TrimSpace(raw.RawRetryAnalyzer)
+	}
+	return nil
 }
 
 // Suite represents a TestNG suite.
 type Results struct {
-	Total      int
-	Failures   int
-	Skipped    int
-	DurationMS float64
+	Total      int     `json:"total"`
+	Failures   int     `json:"failures"`
+	Skipped    int     `json:"skipped"`
+	Errors     int     `json:"errors"`
+	DurationMS float64 `json:"duration_ms"`
+
+	// ConfigSkips counts the skipped configuration methods, which are also
+	// included in Skipped.
+	ConfigSkips int `json:"config_skips"`
+
+	// AssertionFailures and ErrorFailures split Failures by the class of the
+	// exception. Failures without an exception are counted in neither.
+	AssertionFailures int `json:"assertion_failures"`
+	ErrorFailures     int `json:"error_failures"`
+
+	// FullySkippedClasses counts the classes whose tests were all skipped.
+	FullySkippedClasses int `json:"fully_skipped_classes"`
+
+	// EmptyClasses counts the classes listed without any test methods, e.g.
+	// because every method was deselected.
+	EmptyClasses int `json:"empty_classes"`
+
+	// MissingExceptions counts the failed tests without exception text.
+	MissingExceptions int `json:"missing_exceptions"`
+
+	// Flaky counts the tests that passed after one or more retried attempts.
+	Flaky int `json:"flaky"`
+
+	// InvalidDurations counts the tests whose duration is missing, malformed,
+	// negative or NaN.
+	InvalidDurations int `json:"invalid_durations"`
+
+	// Unknown counts the tests whose status is not recognized as passed,
+	// failed or skipped.
+	Unknown int `json:"unknown"`
+
+	// Assertions sums the assertion counts of the AssertionsReported tests
+	// that recorded one.
+	Assertions         int `json:"assertions"`
+	AssertionsReported int `json:"assertions_reported"`
+}
+
+// add accumulates other into r.
+func (r *Results) add(other Results) {
+	r.Total += other.Total
+	r.Failures += other.Failures
+	r.Skipped += other.Skipped
+	r.Errors += other.Errors
+	r.DurationMS += other.DurationMS
+	r.ConfigSkips += other.ConfigSkips
+	r.AssertionFailures += other.AssertionFailures
+	r.ErrorFailures += other.ErrorFailures
+	r.FullySkippedClasses += other.FullySkippedClasses
+	r.EmptyClasses += other.EmptyClasses
+	r.MissingExceptions += other.MissingExceptions
+	r.Flaky += other.Flaky
+	r.Unknown += other.Unknown
+	r.InvalidDurations += other.InvalidDurations
+	r.Assertions += other.Assertions
+	r.AssertionsReported += other.AssertionsReported
+}
+
+// RunRecord is the aggregate outcome of a single plugin run, as persisted
+// to run history outputs.
+type RunRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Label      string    `json:"label"`
+	Total      int       `json:"total"`
+	Failures   int       `json:"failures"`
+	Skipped    int       `json:"skipped"`
+	DurationMS float64   `json:"duration_ms"`
+	Flaky      int       `json:"flaky"`
+}
+
+// newRunRecord creates a run record from aggregated results.
+func newRunRecord(results Results, label string, timestamp time.Time) RunRecord {
+	return RunRecord{
+		Timestamp:  timestamp,
+		Label:      label,
+		Total:      results.Total,
+		Failures:   results.Failures,
+		Skipped:    results.Skipped,
+		DurationMS: results.DurationMS,
+		Flaky:      results.Flaky,
+	}
 }
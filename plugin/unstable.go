@@ -0,0 +1,50 @@
+package plugin
+
+import "github.com/sirupsen/logrus"
+
+// BuildStateOutput is the output variable set when the build is unstable.
+const BuildStateOutput = "TESTNG_BUILD_STATE"
+
+// BuildStateUnstable is the value of BuildStateOutput for an unstable build.
+const BuildStateUnstable = "unstable"
+
+// checkUnstable returns an error describing the first unstable threshold that
+// results breach. UnstableFails and UnstableSkips are interpreted like
+// FailedFails and FailedSkips, as counts or percentages per ThresholdMode.
+func checkUnstable(results Results, args Args) error {
+	if args.UnstableFails == 0 && args.UnstableSkips == 0 {
+		return nil
+	}
+
+	if args.ThresholdMode == ThresholdModePercentage {
+		if results.Total == 0 {
+			return nil
+		}
+		failureRate := float64(results.Failures) / float64(results.Total) * 100
+		skipRate := float64(results.Skipped) / float64(results.Total) * 100
+		if err := checkThreshold("failure", failureRate, float64(args.UnstableFails), true); err != nil {
+			return err
+		}
+		return checkThreshold("skip", skipRate, float64(args.UnstableSkips), true)
+	}
+
+	if err := checkThreshold("failed", float64(results.Failures), float64(args.UnstableFails), false); err != nil {
+		return err
+	}
+	return checkThreshold("skipped", float64(results.Skipped), float64(args.UnstableSkips), false)
+}
+
+// markUnstable logs and sets the TESTNG_BUILD_STATE output variable when
+// results breach an unstable threshold. It is only called once the fail
+// thresholds have passed, so an unstable build does not fail the step.
+func markUnstable(results Results, args Args) {
+	err := checkUnstable(results, args)
+	if err == nil {
+		return
+	}
+
+	logrus.Warnf("\n!!! build marked as unstable as the %s !!!", err.Error())
+	if err := writeOutputVariable(BuildStateOutput, BuildStateUnstable); err != nil {
+		logrus.Warn(err.Error())
+	}
+}
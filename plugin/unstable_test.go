@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecUnstableBuildState(t *testing.T) {
+	// The sample report has a failure rate of 33.33%
+	tests := []struct {
+		name          string
+		unstableFails int
+		failedFails   int
+		wantErr       bool
+		wantOutput    string
+	}{
+		{name: "Passed", unstableFails: 50, failedFails: 60, wantOutput: ""},
+		{name: "Unstable", unstableFails: 20, failedFails: 50, wantOutput: "TESTNG_BUILD_STATE=unstable\n"},
+		{name: "Failed", unstableFails: 10, failedFails: 20, wantErr: true, wantOutput: "TESTNG_FAIL_REASON=PERCENTAGE_FAILURES\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "drone-output.env")
+			t.Setenv("DRONE_OUTPUT", outputFile)
+
+			args := Args{
+				ReportFilenamePattern: "../testdata/testng-report.xml",
+				ThresholdMode:         ThresholdModePercentage,
+				UnstableFails:         tt.unstableFails,
+				FailedFails:           tt.failedFails,
+			}
+			if err := ValidateInputs(args); err != nil {
+				t.Fatalf("ValidateInputs() unexpected error: %v", err)
+			}
+			err := Exec(context.Background(), args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			data, readErr := os.ReadFile(outputFile)
+			if readErr != nil && !os.IsNotExist(readErr) {
+				t.Fatalf("Failed to read output file: %v", readErr)
+			}
+			if got := string(data); got != tt.wantOutput {
+				t.Errorf("Output variables = %q, want %q", got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestValidateInputsUnstableThresholds(t *testing.T) {
+	args := Args{ThresholdMode: ThresholdModeAbsolute, UnstableFails: 5, FailedFails: 5}
+	if err := ValidateInputs(args); err == nil {
+		t.Errorf("Expected an error when UnstableFails is not lower than FailedFails")
+	}
+
+	args = Args{ThresholdMode: ThresholdModeAbsolute, UnstableSkips: -1}
+	if err := ValidateInputs(args); err == nil {
+		t.Errorf("Expected an error for a negative UnstableSkips")
+	}
+}
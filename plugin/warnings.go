@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// warningCounter is a logrus hook that counts the warnings logged while it
+// is installed.
+type warningCounter struct {
+	count int64
+	hooks logrus.LevelHooks
+}
+
+// installWarningCounter adds a warning counter to the standard logger.
+// Call remove to restore the previously installed hooks.
+func installWarningCounter() *warningCounter {
+	logger := logrus.StandardLogger()
+
+	// Keep a copy of the current hooks so they can be restored
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		hooks[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+
+	counter := &warningCounter{hooks: hooks}
+	logger.AddHook(counter)
+	return counter
+}
+
+// Levels returns the log levels counted by the hook.
+func (w *warningCounter) Levels() []logrus.Level {
+	return []logrus.Level{logrus.WarnLevel}
+}
+
+// Fire is called for each warning entry.
+func (w *warningCounter) Fire(*logrus.Entry) error {
+	atomic.AddInt64(&w.count, 1)
+	return nil
+}
+
+// Count returns the number of warnings logged so far.
+func (w *warningCounter) Count() int {
+	return int(atomic.LoadInt64(&w.count))
+}
+
+// remove uninstalls the counter by restoring the previous hooks.
+func (w *warningCounter) remove() {
+	logrus.StandardLogger().ReplaceHooks(w.hooks)
+}
+
+// warningDeduper collects per-test warnings while reports are processed, so
+// a warning repeated for many tests is logged once with its count.
+type warningDeduper struct {
+	mu     sync.Mutex
+	counts map[string]int
+	order  []string
+}
+
+var (
+	dedupeMu      sync.Mutex
+	activeDeduper *warningDeduper
+)
+
+// installWarningDeduper starts collecting the warnings logged with warnf.
+// Call flush to log them and remove to stop collecting.
+func installWarningDeduper() *warningDeduper {
+	d := &warningDeduper{counts: make(map[string]int)}
+	dedupeMu.Lock()
+	activeDeduper = d
+	dedupeMu.Unlock()
+	return d
+}
+
+// warnf logs a warning, or collects it when a deduper is installed.
+func warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+
+	dedupeMu.Lock()
+	d := activeDeduper
+	dedupeMu.Unlock()
+	if d == nil {
+		logrus.Warn(msg)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.counts[msg] == 0 {
+		d.order = append(d.order, msg)
+	}
+	d.counts[msg]++
+}
+
+// flush logs each collected warning once, in the order first seen, with a
+// count suffix such as "(x42)" when it was repeated.
+func (d *warningDeduper) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, msg := range d.order {
+		if count := d.counts[msg]; count > 1 {
+			logrus.Warnf("%s (x%d)", msg, count)
+		} else {
+			logrus.Warn(msg)
+		}
+	}
+	d.counts = make(map[string]int)
+	d.order = nil
+}
+
+// remove stops collecting warnings.
+func (d *warningDeduper) remove() {
+	dedupeMu.Lock()
+	defer dedupeMu.Unlock()
+	if activeDeduper == d {
+		activeDeduper = nil
+	}
+}
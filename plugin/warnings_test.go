@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestExecFailOnWarnings(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+
+	args := Args{
+		ReportFilenamePattern: "../testdata/warnings/testng-invalid-duration.xml",
+		ThresholdMode:         ThresholdModeAbsolute,
+	}
+
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Exec() unexpected error without FailOnWarnings: %v", err)
+	}
+
+	args.FailOnWarnings = true
+	err := Exec(context.Background(), args)
+	if err == nil || !strings.Contains(err.Error(), "1 warning(s) were logged") {
+		t.Errorf("Exec() expected a warnings error, got %v", err)
+	}
+
+	// The counter is removed once Exec returns
+	for _, hook := range logrus.StandardLogger().Hooks[logrus.WarnLevel] {
+		if _, ok := hook.(*warningCounter); ok {
+			t.Errorf("Warning counter hook was not removed")
+		}
+	}
+}
+
+func TestExecDeduplicatesWarnings(t *testing.T) {
+	logrus.SetLevel(logrus.InfoLevel)
+	hook := NewMockLogHook()
+	logrus.AddHook(hook)
+
+	// A data provider runs the same method 42 times, each with a bad duration
+	var methods strings.Builder
+	for i := 0; i < 42; i++ {
+		methods.WriteString(`<test-method status="PASS" signature="param()" name="param" duration-ms="n/a"/>`)
+	}
+	methods.WriteString(`<test-method status="PASS" signature="other()" name="other" duration-ms="n/a"/>`)
+	report := `<testng-results><suite name="DedupeSuite"><test name="test1"><class name="com.test.Params">` +
+		methods.String() + `</class></test></suite></testng-results>`
+	reportFile := filepath.Join(t.TempDir(), "testng-results.xml")
+	if err := os.WriteFile(reportFile, []byte(report), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	args := Args{
+		ReportFilenamePattern: reportFile,
+		ThresholdMode:         ThresholdModeAbsolute,
+	}
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	var warnings []string
+	for _, entry := range hook.Entries {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	expected := []string{
+		`Invalid or missing DurationMS for test 'param': strconv.ParseFloat: parsing "n/a": invalid syntax (x42)`,
+		`Invalid or missing DurationMS for test 'other': strconv.ParseFloat: parsing "n/a": invalid syntax`,
+	}
+	if diff := cmp.Diff(expected, warnings); diff != "" {
+		t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+	}
+}